@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,10 +14,21 @@ import (
 
 	"github.com/makson2134/go-qa-service/internal/api"
 	"github.com/makson2134/go-qa-service/internal/api/handlers"
+	"github.com/makson2134/go-qa-service/internal/api/middleware"
+	"github.com/makson2134/go-qa-service/internal/auth"
 	"github.com/makson2134/go-qa-service/internal/config"
+	"github.com/makson2134/go-qa-service/internal/health"
+	"github.com/makson2134/go-qa-service/internal/metrics"
+	"github.com/makson2134/go-qa-service/internal/ratelimit"
 	"github.com/makson2134/go-qa-service/internal/repository/postgres"
+	"github.com/makson2134/go-qa-service/internal/service"
+	"github.com/makson2134/go-qa-service/internal/tracing"
+	"github.com/makson2134/go-qa-service/internal/trash"
+	"github.com/makson2134/go-qa-service/internal/webhook"
 	"github.com/makson2134/go-qa-service/pkg"
 	"github.com/pressly/goose/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 func main() {
@@ -31,6 +44,23 @@ func main() {
 
 	logger := pkg.NewLogger(cfg.Log.Level, cfg.Log.Format)
 
+	tp, err := tracing.NewProvider(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		log.Fatal(err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := tp.Shutdown(ctx); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
 	dsn, err := cfg.Database.GetDSN()
 	if err != nil {
 		logger.Error("failed to get DSN", "error", err)
@@ -42,6 +72,7 @@ func main() {
 		cfg.Database.MaxOpenConns,
 		cfg.Database.MaxIdleConns,
 		cfg.Database.ConnMaxLifetime,
+		tp,
 	)
 	if err != nil {
 		logger.Error("failed to connect to database", "error", err)
@@ -72,9 +103,130 @@ func main() {
 	}
 	logger.Info("Migrations applied successfully")
 
-	h := handlers.New(db, db, logger)
+	m := metrics.New()
+	if err := m.RegisterDB("postgres", sqlDB); err != nil {
+		logger.Error("failed to register database metrics", "error", err)
+		log.Fatal(err)
+	}
+
+	questions := service.NewQuestionService(db, m)
+	answers := service.NewAnswerService(db, db, m)
+
+	readiness := health.New(cfg.Server.ReadinessTimeout)
+	readiness.Add("database", func(ctx context.Context) (any, error) {
+		return nil, sqlDB.PingContext(ctx)
+	})
+	readiness.Add("migrations", func(ctx context.Context) (any, error) {
+		version, err := goose.GetDBVersionContext(ctx, sqlDB)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]int64{"version": version}, nil
+	})
+
+	// Stopped before the database connection is closed
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
+	if cfg.Trash.PurgeInterval > 0 {
+		go func() {
+			defer close(purgeDone)
+			trash.NewPurger(db, cfg.Trash.Retention, cfg.Trash.PurgeInterval, logger).Run(purgeCtx)
+		}()
+	} else {
+		close(purgeDone)
+		logger.Warn("trash purge is disabled, deleted questions and answers are kept forever")
+	}
+	defer func() {
+		stopPurge()
+		<-purgeDone
+	}()
+
+	// Also stopped before the database connection is closed, deliveries in flight are sent again once their lease is over
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	dispatchDone := make(chan struct{})
+	if cfg.Webhooks.PollInterval > 0 {
+		backoff := webhook.Backoff{Min: cfg.Webhooks.MinBackoff, Max: cfg.Webhooks.MaxBackoff, MaxAttempts: cfg.Webhooks.MaxAttempts}
+		go func() {
+			defer close(dispatchDone)
+			webhook.NewDispatcher(db, cfg.Webhooks.PollInterval, cfg.Webhooks.Timeout, backoff, logger).Run(dispatchCtx)
+		}()
+	} else {
+		close(dispatchDone)
+		logger.Warn("webhook dispatch is disabled on this replica, deliveries are left to the others")
+	}
+	defer func() {
+		stopDispatch()
+		<-dispatchDone
+	}()
+
+	h := handlers.New(handlers.Deps{
+		Questions: questions,
+		Answers:   answers,
+		Comments:  db,
+		Users:     db,
+		Tags:      db,
+		Search:    db,
+		Trash:     db,
+		AuditLog:  db,
+		Webhooks:  db,
+		Readiness: readiness,
+	}, logger)
+
+	authenticate, err := newAuthMiddleware(&cfg.Auth)
+	if err != nil {
+		logger.Error("failed to set up authentication", "error", err)
+		log.Fatal(err)
+	}
+	if cfg.Auth.Mode == config.AuthModeAnonymous {
+		logger.Warn("authentication is disabled, users are taken from X-User-ID header")
+	}
+
+	clientIP, err := middleware.ClientIP(cfg.RateLimit.TrustedProxies)
+	if err != nil {
+		logger.Error("failed to parse trusted proxies", "error", err)
+		log.Fatal(err)
+	}
+
+	ipRateLimit, rateLimit := newRateLimitMiddleware(&cfg.RateLimit, db, clientIP, logger)
+
+	// Probes and scrapes come often from a few addresses and must not be turned away
+	unlimited := []string{"/livez", "/readyz", "/health"}
+	if cfg.Metrics.Enabled {
+		unlimited = append(unlimited, cfg.Metrics.Path)
+	}
+
+	routes := api.SetupRoutes(h)
+	// The limit per IP runs before authentication and the one per user after it, both under the timeout
+	// as their store may be the database
+	mux := middleware.Audit(clientIP)(middleware.SkipPaths(unlimited, rateLimit)(routes))
+	mux = middleware.Timeout(cfg.Server.DBTimeout)(middleware.SkipPaths(unlimited, ipRateLimit)(authenticate(mux)))
+	// Inside the server span, so that request log lines carry the trace ID
+	mux = middleware.RequestID(middleware.AccessLog(logger)(middleware.Recover(logger)(mux)))
+	mux = middleware.Tracing(tp, routes.Route)(mux)
+
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		mux = middleware.Metrics(m, routes.Route)(mux)
+
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle(cfg.Metrics.Path, m.Handler())
+
+		if cfg.Metrics.Port == "" {
+			metricsMux.Handle("/", mux)
+			mux = metricsMux
+		} else {
+			metricsServer = &http.Server{
+				Addr:              ":" + cfg.Metrics.Port,
+				Handler:           metricsMux,
+				ReadHeaderTimeout: cfg.Server.ReadTimeout,
+			}
+		}
+	}
 
-	mux := api.SetupRoutes(h)
+	// Cancelled after the shutdown deadline, so queries of the remaining requests don't keep running
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -82,6 +234,9 @@ func main() {
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return requestsCtx
+		},
 	}
 
 	go func() {
@@ -93,22 +248,84 @@ func main() {
 		}
 	}()
 
+	if metricsServer != nil {
+		go func() {
+			logger.Info("starting metrics server", "port", cfg.Metrics.Port, "path", cfg.Metrics.Path)
+
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("metrics server failed", "error", err)
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
 	sig := <-quit
 	logger.Info("received shutdown signal", "signal", sig)
 
+	// Failing /readyz first lets load balancers stop sending requests before connections are closed
+	readiness.Drain()
+	logger.Info("draining traffic", "delay", cfg.Server.DrainDelay)
+	time.Sleep(cfg.Server.DrainDelay)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	logger.Info("shutting down server gracefully")
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("server forced to shutdown", "error", err)
+		cancelRequests()
+	}
+
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			logger.Error("metrics server forced to shutdown", "error", err)
+		}
 	}
 
 	logger.Info("server stopped")
 }
+
+func newAuthMiddleware(cfg *config.AuthConfig) (func(http.Handler) http.Handler, error) {
+	admins := middleware.Admins(cfg.Admins)
+
+	if cfg.Mode == config.AuthModeAnonymous {
+		return func(next http.Handler) http.Handler {
+			return middleware.Anonymous(admins(next))
+		}, nil
+	}
+
+	key, err := cfg.GetKey()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := auth.NewVerifier(cfg.Algorithm, key, cfg.Issuer, cfg.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return middleware.Auth(verifier)(admins(next))
+	}, nil
+}
+
+// newRateLimitMiddleware returns the limit per client IP and the one per user, sharing a store.
+func newRateLimitMiddleware(cfg *config.RateLimitConfig, db *postgres.DB, clientIP func(r *http.Request) string, logger *slog.Logger) (perIP, perUser func(http.Handler) http.Handler) {
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.Store == config.RateLimitStorePostgres {
+		store = postgres.NewRateLimitStore(db, logger)
+	}
+
+	ip := ratelimit.Limit{Rate: float64(cfg.IPPerMinute) / 60, Burst: cfg.IPBurst}
+	reads := ratelimit.Limit{Rate: float64(cfg.ReadsPerMinute) / 60, Burst: cfg.ReadBurst}
+	writes := ratelimit.Limit{Rate: float64(cfg.WritesPerMinute) / 60, Burst: cfg.WriteBurst}
+
+	return middleware.IPRateLimit(store, ip, clientIP, logger), middleware.RateLimit(store, reads, writes, clientIP, logger)
+}
+
 func findConfigFile(dir string) (string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -3,14 +3,40 @@ package dto
 import "time"
 
 type CreateAnswerRequest struct {
-	UserID string `json:"user_id"`
-	Text   string `json:"text"`
+	Body string `json:"body"` // Markdown
+}
+
+type UpdateAnswerRequest struct {
+	Body string `json:"body"`
 }
 
 type AnswerResponse struct {
+	ID           int       `json:"id"`
+	QuestionID   int       `json:"question_id"`
+	UserID       string    `json:"user_id"`
+	BodyMarkdown string    `json:"body_markdown"`
+	BodyHTML     string    `json:"body_html"`
+	Score        int       `json:"score"`
+	IsAccepted   bool      `json:"is_accepted"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Comments []CommentResponse `json:"comments,omitempty"` // Only with ?include=comments
+}
+
+type AnswerPageResponse struct {
+	Answers    []AnswerResponse `json:"answers"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// AnswerSummaryResponse is an answer in listings, with a plain text excerpt instead of the body.
+type AnswerSummaryResponse struct {
 	ID         int       `json:"id"`
 	QuestionID int       `json:"question_id"`
 	UserID     string    `json:"user_id"`
-	Text       string    `json:"text"`
+	Excerpt    string    `json:"excerpt"`
+	Score      int       `json:"score"`
+	IsAccepted bool      `json:"is_accepted"`
 	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
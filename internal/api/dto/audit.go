@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEventResponse is a change to an entity. Before and After are the entity as it was stored,
+// null when it didn't exist before the change or is gone after it.
+type AuditEventResponse struct {
+	ID         int             `json:"id"`
+	Actor      *string         `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  *string         `json:"request_id"`
+	ClientIP   *string         `json:"client_ip"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditListResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
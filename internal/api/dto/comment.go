@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+type CreateCommentRequest struct {
+	Body     string `json:"body"`      // Markdown
+	ParentID *int   `json:"parent_id"` // Top-level comment to reply to
+}
+
+type CommentResponse struct {
+	ID           int               `json:"id"`
+	QuestionID   *int              `json:"question_id,omitempty"`
+	AnswerID     *int              `json:"answer_id,omitempty"`
+	ParentID     *int              `json:"parent_id,omitempty"`
+	UserID       string            `json:"user_id"`
+	BodyMarkdown string            `json:"body_markdown"`
+	BodyHTML     string            `json:"body_html"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Replies      []CommentResponse `json:"replies,omitempty"`
+}
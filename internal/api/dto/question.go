@@ -3,18 +3,70 @@ package dto
 import "time"
 
 type CreateQuestionRequest struct {
-	Text string `json:"text"`
+	Title string   `json:"title"`
+	Body  string   `json:"body"` // Markdown
+	Tags  []string `json:"tags"`
+}
+
+// UpdateQuestionRequest is used for both PUT and PATCH. PUT requires every field,
+// fields absent from a PATCH are left unchanged.
+type UpdateQuestionRequest struct {
+	Title *string   `json:"title"`
+	Body  *string   `json:"body"`
+	Tags  *[]string `json:"tags"`
 }
 
 type QuestionResponse struct {
-	ID        int       `json:"id"`
-	Text      string    `json:"text"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               int       `json:"id"`
+	AuthorID         *string   `json:"author_id"`
+	Title            string    `json:"title"`
+	BodyMarkdown     string    `json:"body_markdown"`
+	BodyHTML         string    `json:"body_html"`
+	Score            int       `json:"score"`
+	AcceptedAnswerID *int      `json:"accepted_answer_id"`
+	Tags             []string  `json:"tags"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// QuestionSummaryResponse is a question in listings, with a plain text excerpt instead of the body.
+type QuestionSummaryResponse struct {
+	ID               int       `json:"id"`
+	AuthorID         *string   `json:"author_id"`
+	Title            string    `json:"title"`
+	Excerpt          string    `json:"excerpt"`
+	Score            int       `json:"score"`
+	AnswersCount     int       `json:"answers_count"`
+	AcceptedAnswerID *int      `json:"accepted_answer_id"`
+	Tags             []string  `json:"tags"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type QuestionListResponse struct {
+	Questions  []QuestionSummaryResponse `json:"questions"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
 }
 
+// QuestionWithAnswersResponse embeds the first page of answers, AnswersNextURL lists the others.
 type QuestionWithAnswersResponse struct {
-	ID        int              `json:"id"`
-	Text      string           `json:"text"`
-	CreatedAt time.Time        `json:"created_at"`
-	Answers   []AnswerResponse `json:"answers"`
+	QuestionResponse
+	Comments       []CommentResponse `json:"comments,omitempty"` // Only with ?include=comments
+	Answers        []AnswerResponse  `json:"answers"`
+	AnswersTotal   int               `json:"answers_total"`
+	AnswersNextURL string            `json:"answers_next_url,omitempty"`
+}
+
+type RevisionResponse struct {
+	ID           int       `json:"id"`
+	Title        string    `json:"title,omitempty"` // Only for question revisions
+	BodyMarkdown string    `json:"body_markdown"`
+	BodyHTML     string    `json:"body_html"`
+	CreatedAt    time.Time `json:"created_at"`
+	ReplacedAt   time.Time `json:"replaced_at"`
+}
+
+type TagResponse struct {
+	Name           string `json:"name"`
+	QuestionsCount int    `json:"questions_count"`
 }
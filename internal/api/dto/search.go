@@ -0,0 +1,14 @@
+package dto
+
+type SearchHitResponse struct {
+	Type       string  `json:"type"`
+	QuestionID int     `json:"question_id"`
+	AnswerID   *int    `json:"answer_id,omitempty"`
+	Rank       float64 `json:"rank"`
+	Snippet    string  `json:"snippet"`
+}
+
+type SearchResponse struct {
+	Query   string              `json:"query"`
+	Results []SearchHitResponse `json:"results"`
+}
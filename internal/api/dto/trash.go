@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// TrashItemResponse is a deleted question or answer, Type tells which one.
+type TrashItemResponse struct {
+	Type       string    `json:"type"`
+	ID         int       `json:"id"`
+	QuestionID int       `json:"question_id,omitempty"` // Only for answers
+	Title      string    `json:"title,omitempty"`       // Only for questions
+	AuthorID   *string   `json:"author_id"`
+	Excerpt    string    `json:"excerpt"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	DeletedBy  *string   `json:"deleted_by"`
+}
+
+type TrashListResponse struct {
+	Items      []TrashItemResponse `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
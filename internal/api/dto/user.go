@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+type RegisterUserRequest struct {
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+}
+
+type UserResponse struct {
+	ID          string    `json:"id"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AnswerListResponse struct {
+	Answers    []AnswerSummaryResponse `json:"answers"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
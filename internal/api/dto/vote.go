@@ -0,0 +1,10 @@
+package dto
+
+type VoteRequest struct {
+	Value int `json:"value"`
+}
+
+type VoteResponse struct {
+	Value int `json:"value"`
+	Score int `json:"score"`
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import "net/http"
+
+// AcceptAnswer handles POST /questions/{id}/accept/{answer_id}.
+// Accepting another answer replaces the previous one.
+func (h *Handlers) AcceptAnswer(w http.ResponseWriter, r *http.Request) {
+	questionID, ok := pathID(w, r, "id", "question")
+	if !ok {
+		return
+	}
+
+	answerID, ok := pathID(w, r, "answer_id", "answer")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.questions.AcceptAnswer(r.Context(), questionID, answerID, userID); err != nil {
+		h.serviceError(w, r, err, "failed to accept answer", "question_id", questionID, "answer_id", answerID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnacceptAnswer handles DELETE /questions/{id}/accept.
+func (h *Handlers) UnacceptAnswer(w http.ResponseWriter, r *http.Request) {
+	questionID, ok := pathID(w, r, "id", "question")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.questions.UnacceptAnswer(r.Context(), questionID, userID); err != nil {
+		h.serviceError(w, r, err, "failed to unaccept answer", "question_id", questionID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/service"
+)
+
+func TestAcceptAnswer(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		target       string
+		expectedCode int
+	}{
+		{name: "author accepts", userID: "asker", target: "/questions/1/accept/11", expectedCode: http.StatusNoContent},
+		{name: "someone else accepts", userID: "intruder", target: "/questions/1/accept/11", expectedCode: http.StatusForbidden},
+		{name: "answer of another question", userID: "asker", target: "/questions/1/accept/99", expectedCode: http.StatusNotFound},
+		{name: "invalid answer id", userID: "asker", target: "/questions/1/accept/abc", expectedCode: http.StatusBadRequest},
+		{name: "unauthenticated", target: "/questions/1/accept/11", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accepted := 0
+			mockQuestions := &mockQuestionService{
+				acceptAnswerFunc: func(id, answerID int, userID string) error {
+					if userID != "asker" {
+						return service.ErrNotAuthor
+					}
+					if answerID == 99 {
+						return service.ErrAnswerNotFound
+					}
+					accepted = answerID
+					return nil
+				},
+			}
+
+			h := newTestHandlers(Deps{Questions: mockQuestions})
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			if tt.userID != "" {
+				req = withUser(req, tt.userID)
+			}
+			w := httptest.NewRecorder()
+
+			h.AcceptAnswer(w, routed(t, "POST /questions/{id}/accept/{answer_id}", req))
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if (accepted != 0) != (tt.expectedCode == http.StatusNoContent) {
+				t.Errorf("unexpected accept of answer %d", accepted)
+			}
+		})
+	}
+}
@@ -2,131 +2,261 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
-	"strings"
 
 	"github.com/makson2134/go-qa-service/internal/api/dto"
-	"gorm.io/gorm"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/markdown"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
 )
 
 func (h *Handlers) CreateAnswer(w http.ResponseWriter, r *http.Request) {
-	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(pathParts) < 2 {
-		http.Error(w, "Invalid URL", http.StatusBadRequest)
+	questionID, ok := pathID(w, r, "id", "question")
+	if !ok {
 		return
 	}
 
-	questionID, err := strconv.Atoi(pathParts[1])
-	if err != nil {
-		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+	userID, ok := h.registeredUser(w, r)
+	if !ok {
 		return
 	}
 
 	var req dto.CreateAnswerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		problem.Write(w, r, problem.InvalidBody())
 		return
 	}
 
-	if strings.TrimSpace(req.UserID) == "" {
-		http.Error(w, "UserID cannot be empty", http.StatusBadRequest)
+	answer, err := h.answers.Create(r.Context(), questionID, userID, req.Body)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to create answer", "question_id", questionID)
 		return
 	}
 
-	if strings.TrimSpace(req.Text) == "" {
-		http.Error(w, "Text cannot be empty", http.StatusBadRequest)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newAnswerResponse(answer)); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// ListAnswers handles GET /questions/{id}/answers, in the order of GetQuestion.
+func (h *Handlers) ListAnswers(w http.ResponseWriter, r *http.Request) {
+	questionID, ok := pathID(w, r, "id", "question")
+	if !ok {
 		return
 	}
 
-	_, err = h.questions.GetByID(questionID)
+	opts, err := parseAnswersPage(r)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			http.Error(w, "Question not found", http.StatusNotFound)
+		problem.Write(w, r, err)
+		return
+	}
+
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		opts.Cursor, err = repository.DecodeCursor(token)
+		if err != nil || opts.Cursor.Sort != string(opts.Sort) {
+			problem.Write(w, r, problem.Invalid("cursor", "cursor is invalid or does not match sort"))
 			return
 		}
-
-		h.log.Error("failed to check question existence", "error", err, "question_id", questionID)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-
-		return
 	}
 
-	answer, err := h.answers.CreateAnswer(questionID, req.UserID, req.Text)
+	answers, next, err := h.answers.List(r.Context(), questionID, opts)
 	if err != nil {
-		h.log.Error("failed to create answer", "error", err, "question_id", questionID)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-
+		h.serviceError(w, r, err, "failed to list answers", "question_id", questionID)
 		return
 	}
 
-	response := dto.AnswerResponse{
-		ID:         answer.ID,
-		QuestionID: answer.QuestionID,
-		UserID:     answer.UserID,
-		Text:       answer.Text,
-		CreatedAt:  answer.CreatedAt,
+	response := dto.AnswerPageResponse{
+		Answers: make([]dto.AnswerResponse, len(answers)),
+	}
+	for i := range answers {
+		response.Answers[i] = newAnswerResponse(&answers[i])
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.Error("failed to encode response", "error", err)
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// parseAnswersPage reads the limit and the sort shared by GetQuestion and ListAnswers.
+func parseAnswersPage(r *http.Request) (repository.ListAnswersOptions, error) {
+	var opts repository.ListAnswersOptions
+	var err error
+
+	if opts.Limit, err = parseLimit(r); err != nil {
+		return opts, err
 	}
+
+	opts.Sort = repository.AnswerSort(r.URL.Query().Get("sort"))
+	switch opts.Sort {
+	case "":
+		opts.Sort = repository.AnswerSortScore
+	case repository.AnswerSortScore, repository.AnswerSortNewest, repository.AnswerSortOldest:
+	default:
+		return opts, problem.Invalid("sort", "sort must be one of score, newest, oldest")
+	}
+
+	return opts, nil
+}
+
+// answersNextURL links to the page of answers after the ones embedded in a question.
+func answersNextURL(questionID int, opts repository.ListAnswersOptions, next *repository.Cursor) string {
+	query := url.Values{}
+	query.Set("sort", string(opts.Sort))
+	query.Set("limit", strconv.Itoa(opts.Limit))
+	query.Set("cursor", next.Encode())
+
+	return "/questions/" + strconv.Itoa(questionID) + "/answers?" + query.Encode()
 }
 
 func (h *Handlers) GetAnswer(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/answers/")
+	id, ok := pathID(w, r, "id", "answer")
+	if !ok {
+		return
+	}
 
-	id, err := strconv.Atoi(idStr)
+	answer, err := h.answers.Get(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Invalid answer ID", http.StatusBadRequest)
+		h.serviceError(w, r, err, "failed to get answer", "id", id)
 		return
 	}
 
-	answer, err := h.answers.GetAnswerByID(id)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAnswerResponse(answer)); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// UpdateAnswer only lets the author of the answer change it.
+func (h *Handlers) UpdateAnswer(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "answer")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.InvalidBody())
+		return
+	}
+
+	answer, err := h.answers.Update(r.Context(), id, userID, req.Body)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			http.Error(w, "Answer not found", http.StatusNotFound)
-			return
-		}
+		h.serviceError(w, r, err, "failed to update answer", "id", id)
+		return
+	}
 
-		h.log.Error("failed to get answer", "error", err, "id", id)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAnswerResponse(answer)); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
 
+func (h *Handlers) ListAnswerRevisions(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "answer")
+	if !ok {
 		return
 	}
 
-	response := dto.AnswerResponse{
-		ID:         answer.ID,
-		QuestionID: answer.QuestionID,
-		UserID:     answer.UserID,
-		Text:       answer.Text,
-		CreatedAt:  answer.CreatedAt,
+	revisions, err := h.answers.ListRevisions(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to list answer revisions", "id", id)
+		return
+	}
+
+	response := make([]dto.RevisionResponse, len(revisions))
+	for i, rev := range revisions {
+		response[i] = dto.RevisionResponse{
+			ID:           rev.ID,
+			BodyMarkdown: rev.Body,
+			BodyHTML:     markdown.ToHTML(rev.Body),
+			CreatedAt:    rev.CreatedAt,
+			ReplacedAt:   rev.ReplacedAt,
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.Error("failed to encode response", "error", err)
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
 	}
 }
 
+// DeleteAnswer moves the answer to the trash, see RestoreAnswer.
 func (h *Handlers) DeleteAnswer(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/answers/")
+	id, ok := pathID(w, r, "id", "answer")
+	if !ok {
+		return
+	}
 
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid answer ID", http.StatusBadRequest)
+	principal, ok := currentPrincipal(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.answers.Delete(r.Context(), id, principal.Subject, principal.Admin); err != nil {
+		h.serviceError(w, r, err, "failed to delete answer", "id", id)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreAnswer handles POST /answers/{id}/restore. The answer is no longer accepted after a restore.
+func (h *Handlers) RestoreAnswer(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "answer")
+	if !ok {
 		return
 	}
 
-	if err := h.answers.DeleteAnswer(id); err != nil {
-		h.log.Error("failed to delete answer", "error", err, "id", id)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	principal, ok := currentPrincipal(w, r)
+	if !ok {
+		return
+	}
 
+	if err := h.answers.Restore(r.Context(), id, principal.Subject, principal.Admin); err != nil {
+		h.serviceError(w, r, err, "failed to restore answer", "id", id)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func newAnswerResponse(a *models.Answer) dto.AnswerResponse {
+	return dto.AnswerResponse{
+		ID:           a.ID,
+		QuestionID:   a.QuestionID,
+		UserID:       a.UserID,
+		BodyMarkdown: a.Body,
+		BodyHTML:     markdown.ToHTML(a.Body),
+		Score:        a.Score,
+		IsAccepted:   a.IsAccepted,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
+
+func newAnswerSummaryResponse(a *models.Answer) dto.AnswerSummaryResponse {
+	return dto.AnswerSummaryResponse{
+		ID:         a.ID,
+		QuestionID: a.QuestionID,
+		UserID:     a.UserID,
+		Excerpt:    markdown.Excerpt(a.Body, excerptLength),
+		Score:      a.Score,
+		IsAccepted: a.IsAccepted,
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+	}
+}
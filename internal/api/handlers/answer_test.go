@@ -5,113 +5,199 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/auth"
 	"github.com/makson2134/go-qa-service/internal/models"
-	"github.com/makson2134/go-qa-service/pkg"
-	"gorm.io/gorm"
+	"github.com/makson2134/go-qa-service/internal/service"
 )
 
+func withUser(req *http.Request, userID string) *http.Request {
+	return req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Subject: userID}))
+}
+
+// routed sets the path values of req the way the router does for pattern.
+func routed(t *testing.T, pattern string, req *http.Request) *http.Request {
+	t.Helper()
+
+	var matched *http.Request
+	mux := http.NewServeMux()
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) { matched = r })
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if matched == nil {
+		t.Fatalf("%s %s doesn't match %q", req.Method, req.URL.Path, pattern)
+	}
+
+	return matched
+}
+
 func TestCreateAnswer_QuestionNotFound(t *testing.T) {
-	mockQuestions := &mockQuestionRepo{
-		getByIDFunc: func(id int) (*models.Question, error) {
-			return nil, gorm.ErrRecordNotFound
+	mockAnswers := &mockAnswerService{
+		createFunc: func(questionID int, userID, body string) (*models.Answer, error) {
+			return nil, service.ErrQuestionNotFound
 		},
 	}
 
-	logger := pkg.NewLogger("error", "json")
-	h := New(mockQuestions, &mockAnswerRepo{}, logger)
+	h := newTestHandlers(Deps{Answers: mockAnswers})
 
 	body := map[string]string{
-		"user_id": "user-123",
-		"text":    "Some answer",
+		"body": "Some answer",
 	}
 	bodyBytes, _ := json.Marshal(body)
 
-	req := httptest.NewRequest(http.MethodPost, "/questions/999/answers/", bytes.NewReader(bodyBytes))
+	req := withUser(httptest.NewRequest(http.MethodPost, "/questions/999/answers/", bytes.NewReader(bodyBytes)), "user-123")
 	w := httptest.NewRecorder()
 
-	h.CreateAnswer(w, req)
+	h.CreateAnswer(w, routed(t, "POST /questions/{id}/answers/{$}", req))
 
 	if w.Code != http.StatusNotFound {
 		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
 	}
 }
 
-func TestCreateAnswer_EmptyText(t *testing.T) {
-	logger := pkg.NewLogger("error", "json")
-	h := New(&mockQuestionRepo{}, &mockAnswerRepo{}, logger)
+func TestCreateAnswer_Unauthenticated(t *testing.T) {
+	h := newTestHandlers(Deps{})
 
-	tests := []struct {
-		name string
-		body map[string]string
-	}{
-		{
-			name: "empty string",
-			body: map[string]string{
-				"user_id": "user-123",
-				"text":    "",
-			},
-		},
-		{
-			name: "whitespace only",
-			body: map[string]string{
-				"user_id": "user-123",
-				"text":    "   ",
-			},
+	bodyBytes, _ := json.Marshal(map[string]string{"body": "Some answer"})
+	req := httptest.NewRequest(http.MethodPost, "/questions/1/answers/", bytes.NewReader(bodyBytes))
+	w := httptest.NewRecorder()
+
+	h.CreateAnswer(w, routed(t, "POST /questions/{id}/answers/{$}", req))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestCreateAnswer_UserFromToken(t *testing.T) {
+	var gotUserID string
+	mockAnswers := &mockAnswerService{
+		createFunc: func(questionID int, userID, body string) (*models.Answer, error) {
+			gotUserID = userID
+			return &models.Answer{ID: 1, QuestionID: questionID, UserID: userID, Body: body}, nil
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			bodyBytes, _ := json.Marshal(tt.body)
-			req := httptest.NewRequest(http.MethodPost, "/questions/1/answers/", bytes.NewReader(bodyBytes))
-			w := httptest.NewRecorder()
+	h := newTestHandlers(Deps{Answers: mockAnswers})
+
+	// user_id in the body is ignored, answers can't be posted on behalf of others
+	bodyBytes, _ := json.Marshal(map[string]string{"user_id": "someone-else", "body": "Some answer"})
+	req := withUser(httptest.NewRequest(http.MethodPost, "/questions/1/answers/", bytes.NewReader(bodyBytes)), "user-123")
+	w := httptest.NewRecorder()
 
-			h.CreateAnswer(w, req)
+	h.CreateAnswer(w, routed(t, "POST /questions/{id}/answers/{$}", req))
 
-			if w.Code != http.StatusBadRequest {
-				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
-			}
-		})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	if gotUserID != "user-123" {
+		t.Errorf("expected answer from %q, got %q", "user-123", gotUserID)
 	}
 }
 
-func TestCreateAnswer_EmptyUserID(t *testing.T) {
-	logger := pkg.NewLogger("error", "json")
-	h := New(&mockQuestionRepo{}, &mockAnswerRepo{}, logger)
+func TestUpdateAnswer_UserFromToken(t *testing.T) {
+	var gotUserID string
+	mockAnswers := &mockAnswerService{
+		updateFunc: func(id int, userID, body string) (*models.Answer, error) {
+			gotUserID = userID
+			if userID != "author" {
+				return nil, service.ErrNotAuthor
+			}
+			return &models.Answer{ID: id, QuestionID: 1, UserID: userID, Body: body}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Answers: mockAnswers})
 
 	tests := []struct {
-		name string
-		body map[string]string
+		name         string
+		userID       string
+		expectedCode int
 	}{
-		{
-			name: "empty string",
-			body: map[string]string{
-				"user_id": "",
-				"text":    "Some answer",
-			},
-		},
-		{
-			name: "whitespace only",
-			body: map[string]string{
-				"user_id": "   ",
-				"text":    "Some answer",
-			},
-		},
+		{name: "another user", userID: "intruder", expectedCode: http.StatusForbidden},
+		{name: "author", userID: "author", expectedCode: http.StatusOK},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			bodyBytes, _ := json.Marshal(tt.body)
-			req := httptest.NewRequest(http.MethodPost, "/questions/1/answers/", bytes.NewReader(bodyBytes))
+			bodyBytes, _ := json.Marshal(map[string]string{"body": "New text"})
+			req := withUser(httptest.NewRequest(http.MethodPatch, "/answers/5", bytes.NewReader(bodyBytes)), tt.userID)
 			w := httptest.NewRecorder()
 
-			h.CreateAnswer(w, req)
+			h.UpdateAnswer(w, routed(t, "PATCH /answers/{id}", req))
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
 
-			if w.Code != http.StatusBadRequest {
-				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			if gotUserID != tt.userID {
+				t.Errorf("expected update by %q, got %q", tt.userID, gotUserID)
 			}
 		})
 	}
 }
+
+func TestListAnswerRevisions(t *testing.T) {
+	replacedAt := time.Date(2026, 1, 20, 10, 0, 0, 0, time.UTC)
+	mockAnswers := &mockAnswerService{
+		listRevisionsFunc: func(id int) ([]models.AnswerRevision, error) {
+			if id == 999 {
+				return nil, service.ErrAnswerNotFound
+			}
+			return []models.AnswerRevision{{ID: 3, AnswerID: id, Body: "Use **channels**", ReplacedAt: replacedAt}}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Answers: mockAnswers})
+
+	req := httptest.NewRequest(http.MethodGet, "/answers/5/revisions", nil)
+	w := httptest.NewRecorder()
+
+	h.ListAnswerRevisions(w, routed(t, "GET /answers/{id}/revisions", req))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var revisions []dto.RevisionResponse
+	if err := json.NewDecoder(w.Body).Decode(&revisions); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(revisions) != 1 || revisions[0].BodyMarkdown != "Use **channels**" || !strings.Contains(revisions[0].BodyHTML, "<strong>channels</strong>") {
+		t.Errorf("expected the revision with its rendered body, got %+v", revisions)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/answers/999/revisions", nil)
+	w = httptest.NewRecorder()
+
+	h.ListAnswerRevisions(w, routed(t, "GET /answers/{id}/revisions", req))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for a missing answer, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestDeleteAnswer_NotFound(t *testing.T) {
+	mockAnswers := &mockAnswerService{
+		deleteFunc: func(id int, userID string, admin bool) error {
+			return service.ErrAnswerNotFound
+		},
+	}
+
+	h := newTestHandlers(Deps{Answers: mockAnswers})
+
+	req := withUser(httptest.NewRequest(http.MethodDelete, "/answers/999", nil), "user-123")
+	w := httptest.NewRecorder()
+
+	h.DeleteAnswer(w, routed(t, "DELETE /answers/{id}", req))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
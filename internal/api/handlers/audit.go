@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+var auditEntities = []string{audit.EntityQuestion, audit.EntityAnswer, audit.EntityComment, audit.EntityUser, audit.EntityWebhook}
+
+// ListAudit handles GET /audit?entity=question&id=42&actor=&from=&to=, only for admins.
+// The newest events come first, from is inclusive and to is exclusive.
+func (h *Handlers) ListAudit(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminUser(w, r); !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := repository.AuditFilter{
+		EntityType: query.Get("entity"),
+		EntityID:   query.Get("id"),
+		Actor:      query.Get("actor"),
+	}
+	if filter.EntityType != "" && !slices.Contains(auditEntities, filter.EntityType) {
+		problem.Write(w, r, problem.Invalid("entity", "entity must be one of: "+strings.Join(auditEntities, ", ")))
+		return
+	}
+	if filter.EntityID != "" && filter.EntityType == "" {
+		problem.Write(w, r, problem.Invalid("entity", "entity is required with id"))
+		return
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(r, "from"); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if filter.To, err = parseTimeParam(r, "to"); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var cursor *repository.Cursor
+	if token := query.Get("cursor"); token != "" {
+		cursor, err = repository.DecodeCursor(token)
+		if err != nil {
+			problem.Write(w, r, problem.Invalid("cursor", "cursor is invalid"))
+			return
+		}
+	}
+
+	events, next, err := h.auditLog.ListAuditEvents(r.Context(), filter, limit, cursor)
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to list audit events", "error", err)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := dto.AuditListResponse{Events: make([]dto.AuditEventResponse, len(events))}
+	for i := range events {
+		response.Events[i] = newAuditEventResponse(&events[i])
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func newAuditEventResponse(e *models.AuditEvent) dto.AuditEventResponse {
+	return dto.AuditEventResponse{
+		ID:         e.ID,
+		Actor:      e.Actor,
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Before:     e.Before,
+		After:      e.After,
+		RequestID:  e.RequestID,
+		ClientIP:   e.ClientIP,
+		CreatedAt:  e.CreatedAt,
+	}
+}
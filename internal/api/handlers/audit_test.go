@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+type mockAuditRepo struct {
+	listAuditEventsFunc func(filter repository.AuditFilter, limit int, cursor *repository.Cursor) ([]models.AuditEvent, *repository.Cursor, error)
+}
+
+func (m *mockAuditRepo) ListAuditEvents(ctx context.Context, filter repository.AuditFilter, limit int, cursor *repository.Cursor) ([]models.AuditEvent, *repository.Cursor, error) {
+	if m.listAuditEventsFunc != nil {
+		return m.listAuditEventsFunc(filter, limit, cursor)
+	}
+	return nil, nil, nil
+}
+
+func TestListAudit(t *testing.T) {
+	actor := "author"
+
+	var gotFilter repository.AuditFilter
+	var gotCursor *repository.Cursor
+	mockAudit := &mockAuditRepo{
+		listAuditEventsFunc: func(filter repository.AuditFilter, limit int, cursor *repository.Cursor) ([]models.AuditEvent, *repository.Cursor, error) {
+			gotFilter, gotCursor = filter, cursor
+			events := []models.AuditEvent{{
+				ID:         9,
+				Actor:      &actor,
+				Action:     "question.updated",
+				EntityType: "question",
+				EntityID:   "42",
+				Before:     json.RawMessage(`{"title":"Old"}`),
+				After:      json.RawMessage(`{"title":"New"}`),
+			}}
+			return events, &repository.Cursor{ID: 9}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{AuditLog: mockAudit})
+
+	tests := []struct {
+		name         string
+		target       string
+		admin        bool
+		expectedCode int
+	}{
+		{name: "entity", target: "/audit?entity=question&id=42", admin: true, expectedCode: http.StatusOK},
+		{name: "actor and time range", target: "/audit?actor=author&from=2026-02-01T00:00:00Z&to=2026-03-01T00:00:00Z", admin: true, expectedCode: http.StatusOK},
+		{name: "unknown entity", target: "/audit?entity=tag", admin: true, expectedCode: http.StatusBadRequest},
+		{name: "id without entity", target: "/audit?id=42", admin: true, expectedCode: http.StatusBadRequest},
+		{name: "bad from", target: "/audit?from=yesterday", admin: true, expectedCode: http.StatusBadRequest},
+		{name: "bad cursor", target: "/audit?cursor=garbage", admin: true, expectedCode: http.StatusBadRequest},
+		{name: "not an admin", target: "/audit", expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.admin {
+				req = withAdmin(req, "moderator")
+			} else {
+				req = withUser(req, "user-123")
+			}
+			w := httptest.NewRecorder()
+
+			h.ListAudit(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body)
+			}
+
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response dto.AuditListResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if len(response.Events) != 1 || string(response.Events[0].After) != `{"title":"New"}` || response.NextCursor == "" {
+				t.Errorf("expected one event with its snapshots and a next cursor, got %+v", response)
+			}
+		})
+	}
+
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/audit?actor=author&from=2026-02-01T00:00:00Z&to=2026-03-01T00:00:00Z&cursor="+(repository.Cursor{ID: 9}).Encode(), nil), "moderator")
+	h.ListAudit(httptest.NewRecorder(), req)
+
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if gotFilter.Actor != "author" || gotFilter.From == nil || !gotFilter.From.Equal(from) || gotFilter.To == nil || !gotFilter.To.Equal(to) {
+		t.Errorf("expected events of author in February, got %+v", gotFilter)
+	}
+	if gotCursor == nil || gotCursor.ID != 9 {
+		t.Errorf("expected cursor after event 9, got %+v", gotCursor)
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/markdown"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+const maxCommentLength = 600
+
+// ListQuestionComments handles GET /questions/{id}/comments.
+func (h *Handlers) ListQuestionComments(w http.ResponseWriter, r *http.Request) {
+	h.listComments(w, r, "question", h.questionCommentTarget)
+}
+
+// CreateQuestionComment handles POST /questions/{id}/comments.
+func (h *Handlers) CreateQuestionComment(w http.ResponseWriter, r *http.Request) {
+	h.createComment(w, r, "question", h.questionCommentTarget)
+}
+
+// ListAnswerComments handles GET /answers/{id}/comments.
+func (h *Handlers) ListAnswerComments(w http.ResponseWriter, r *http.Request) {
+	h.listComments(w, r, "answer", h.answerCommentTarget)
+}
+
+// CreateAnswerComment handles POST /answers/{id}/comments.
+func (h *Handlers) CreateAnswerComment(w http.ResponseWriter, r *http.Request) {
+	h.createComment(w, r, "answer", h.answerCommentTarget)
+}
+
+// commentTargetFunc returns an error of the services when there is nothing to comment on.
+type commentTargetFunc func(ctx context.Context, id int) (repository.CommentTarget, error)
+
+func (h *Handlers) questionCommentTarget(ctx context.Context, id int) (repository.CommentTarget, error) {
+	if _, err := h.questions.Get(ctx, id); err != nil {
+		return repository.CommentTarget{}, err
+	}
+
+	return repository.CommentTarget{QuestionID: id}, nil
+}
+
+func (h *Handlers) answerCommentTarget(ctx context.Context, id int) (repository.CommentTarget, error) {
+	if _, err := h.answers.Get(ctx, id); err != nil {
+		return repository.CommentTarget{}, err
+	}
+
+	return repository.CommentTarget{AnswerID: id}, nil
+}
+
+// commentTarget responds with an error itself when the target can't be resolved.
+func (h *Handlers) commentTarget(w http.ResponseWriter, r *http.Request, entity string, resolve commentTargetFunc) (repository.CommentTarget, bool) {
+	id, ok := pathID(w, r, "id", entity)
+	if !ok {
+		return repository.CommentTarget{}, false
+	}
+
+	target, err := resolve(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to check "+entity+" existence", "id", id)
+		return repository.CommentTarget{}, false
+	}
+
+	return target, true
+}
+
+func (h *Handlers) listComments(w http.ResponseWriter, r *http.Request, entity string, resolve commentTargetFunc) {
+	target, ok := h.commentTarget(w, r, entity, resolve)
+	if !ok {
+		return
+	}
+
+	comments, err := h.comments.ListComments(r.Context(), target)
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to list comments", "error", err, "entity", entity, "target", target)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(commentThreads(comments)); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func (h *Handlers) createComment(w http.ResponseWriter, r *http.Request, entity string, resolve commentTargetFunc) {
+	userID, ok := h.registeredUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.InvalidBody())
+		return
+	}
+
+	if strings.TrimSpace(req.Body) == "" {
+		problem.Write(w, r, problem.Invalid("body", "Body cannot be empty"))
+		return
+	}
+
+	if utf8.RuneCountInString(req.Body) > maxCommentLength {
+		problem.Write(w, r, problem.Invalid("body", fmt.Sprintf("Comment must be at most %d characters", maxCommentLength)))
+		return
+	}
+
+	target, ok := h.commentTarget(w, r, entity, resolve)
+	if !ok {
+		return
+	}
+
+	if req.ParentID != nil {
+		parent, err := h.comments.GetCommentByID(r.Context(), *req.ParentID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			h.logger(r).ErrorContext(r.Context(), "failed to get parent comment", "error", err, "id", *req.ParentID)
+			problem.Write(w, r, problem.Internal())
+
+			return
+		}
+
+		if err != nil || !commentOn(parent, target) {
+			problem.Write(w, r, problem.Invalid("parent_id", "Parent comment not found on this "+entity))
+			return
+		}
+
+		if parent.ParentID != nil {
+			problem.Write(w, r, problem.Invalid("parent_id", "Only top-level comments can be replied to"))
+			return
+		}
+	}
+
+	comment, err := h.comments.CreateComment(r.Context(), target, req.ParentID, userID, req.Body)
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to create comment", "error", err, "entity", entity, "target", target)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newCommentResponse(comment)); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// DeleteComment only lets the author remove the comment, replies to it are removed too.
+func (h *Handlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "comment")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := h.comments.GetCommentByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, errCommentNotFound)
+			return
+		}
+
+		h.logger(r).ErrorContext(r.Context(), "failed to get comment", "error", err, "id", id)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	if comment.UserID != userID {
+		problem.Write(w, r, problem.Forbidden("not_author", "Only the author can delete this comment"))
+		return
+	}
+
+	if err := h.comments.DeleteComment(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, errCommentNotFound)
+			return
+		}
+
+		h.logger(r).ErrorContext(r.Context(), "failed to delete comment", "error", err, "id", id)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func commentOn(c *models.Comment, target repository.CommentTarget) bool {
+	if target.QuestionID != 0 {
+		return c.QuestionID != nil && *c.QuestionID == target.QuestionID
+	}
+
+	return c.AnswerID != nil && *c.AnswerID == target.AnswerID
+}
+
+// commentThreads nests replies under their parents, comments must be ordered oldest first.
+func commentThreads(comments []models.Comment) []dto.CommentResponse {
+	threads := make([]dto.CommentResponse, 0, len(comments))
+	positions := make(map[int]int, len(comments))
+
+	for i := range comments {
+		comment := &comments[i]
+
+		if comment.ParentID == nil {
+			positions[comment.ID] = len(threads)
+			threads = append(threads, newCommentResponse(comment))
+
+			continue
+		}
+
+		if pos, ok := positions[*comment.ParentID]; ok {
+			threads[pos].Replies = append(threads[pos].Replies, newCommentResponse(comment))
+		}
+	}
+
+	return threads
+}
+
+func newCommentResponse(c *models.Comment) dto.CommentResponse {
+	return dto.CommentResponse{
+		ID:           c.ID,
+		QuestionID:   c.QuestionID,
+		AnswerID:     c.AnswerID,
+		ParentID:     c.ParentID,
+		UserID:       c.UserID,
+		BodyMarkdown: c.Body,
+		BodyHTML:     markdown.ToHTML(c.Body),
+		CreatedAt:    c.CreatedAt,
+	}
+}
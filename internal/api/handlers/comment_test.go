@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+type mockCommentRepo struct {
+	createCommentFunc        func(target repository.CommentTarget, parentID *int, userID, body string) (*models.Comment, error)
+	getCommentByIDFunc       func(id int) (*models.Comment, error)
+	listQuestionCommentsFunc func(questionID int) ([]models.Comment, error)
+	deleteCommentFunc        func(id int) error
+}
+
+func (m *mockCommentRepo) CreateComment(ctx context.Context, target repository.CommentTarget, parentID *int, userID, body string) (*models.Comment, error) {
+	if m.createCommentFunc != nil {
+		return m.createCommentFunc(target, parentID, userID, body)
+	}
+	return &models.Comment{ID: 1, ParentID: parentID, UserID: userID, Body: body}, nil
+}
+
+func (m *mockCommentRepo) GetCommentByID(ctx context.Context, id int) (*models.Comment, error) {
+	if m.getCommentByIDFunc != nil {
+		return m.getCommentByIDFunc(id)
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockCommentRepo) ListComments(ctx context.Context, target repository.CommentTarget) ([]models.Comment, error) {
+	return nil, nil
+}
+
+func (m *mockCommentRepo) ListQuestionComments(ctx context.Context, questionID int) ([]models.Comment, error) {
+	if m.listQuestionCommentsFunc != nil {
+		return m.listQuestionCommentsFunc(questionID)
+	}
+	return nil, nil
+}
+
+func (m *mockCommentRepo) DeleteComment(ctx context.Context, id int) error {
+	if m.deleteCommentFunc != nil {
+		return m.deleteCommentFunc(id)
+	}
+	return nil
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestCreateQuestionComment(t *testing.T) {
+	comments := map[int]*models.Comment{
+		1: {ID: 1, QuestionID: intPtr(1), UserID: "author"},
+		2: {ID: 2, QuestionID: intPtr(1), ParentID: intPtr(1), UserID: "author"},
+		3: {ID: 3, QuestionID: intPtr(2), UserID: "author"},
+		4: {ID: 4, AnswerID: intPtr(1), UserID: "author"},
+	}
+
+	var gotTarget repository.CommentTarget
+	var gotParentID *int
+	mockComments := &mockCommentRepo{
+		getCommentByIDFunc: func(id int) (*models.Comment, error) {
+			if comment, ok := comments[id]; ok {
+				return comment, nil
+			}
+			return nil, repository.ErrNotFound
+		},
+		createCommentFunc: func(target repository.CommentTarget, parentID *int, userID, body string) (*models.Comment, error) {
+			gotTarget, gotParentID = target, parentID
+			return &models.Comment{ID: 10, QuestionID: &target.QuestionID, ParentID: parentID, UserID: userID, Body: body}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Comments: mockComments})
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{name: "top-level comment", body: `{"body": "Which Go version?"}`, expectedCode: http.StatusCreated},
+		{name: "reply", body: `{"body": "1.25", "parent_id": 1}`, expectedCode: http.StatusCreated},
+		{name: "empty body", body: `{"body": "  "}`, expectedCode: http.StatusBadRequest},
+		{name: "too long", body: `{"body": "` + strings.Repeat("a", maxCommentLength+1) + `"}`, expectedCode: http.StatusBadRequest},
+		{name: "reply to reply", body: `{"body": "Nested", "parent_id": 2}`, expectedCode: http.StatusBadRequest},
+		{name: "parent on another question", body: `{"body": "Wrong", "parent_id": 3}`, expectedCode: http.StatusBadRequest},
+		{name: "parent on an answer", body: `{"body": "Wrong", "parent_id": 4}`, expectedCode: http.StatusBadRequest},
+		{name: "missing parent", body: `{"body": "Wrong", "parent_id": 99}`, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodPost, "/questions/1/comments", bytes.NewReader([]byte(tt.body))), "user-123")
+			w := httptest.NewRecorder()
+
+			h.CreateQuestionComment(w, routed(t, "POST /questions/{id}/comments", req))
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+		})
+	}
+
+	if gotTarget.QuestionID != 1 || gotParentID == nil || *gotParentID != 1 {
+		t.Errorf("expected reply to comment 1 on question 1, got %+v, parent %v", gotTarget, gotParentID)
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	mockComments := &mockCommentRepo{
+		getCommentByIDFunc: func(id int) (*models.Comment, error) {
+			return &models.Comment{ID: id, QuestionID: intPtr(1), UserID: "author"}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Comments: mockComments})
+
+	tests := []struct {
+		name         string
+		userID       string
+		expectedCode int
+	}{
+		{name: "author", userID: "author", expectedCode: http.StatusNoContent},
+		{name: "someone else", userID: "stranger", expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodDelete, "/comments/5", nil), tt.userID)
+			w := httptest.NewRecorder()
+
+			h.DeleteComment(w, routed(t, "DELETE /comments/{id}", req))
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestGetQuestion_IncludeComments(t *testing.T) {
+	mockQuestions := &mockQuestionService{
+		getFunc: func(id int) (*models.Question, error) {
+			return &models.Question{ID: id, Title: "What is Go?", AnswersCount: 1}, nil
+		},
+	}
+	mockAnswers := &mockAnswerService{
+		listFunc: func(questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error) {
+			return []models.Answer{{ID: 7, QuestionID: questionID}}, nil, nil
+		},
+	}
+	mockComments := &mockCommentRepo{
+		listQuestionCommentsFunc: func(questionID int) ([]models.Comment, error) {
+			return []models.Comment{
+				{ID: 1, QuestionID: intPtr(questionID)},
+				{ID: 2, AnswerID: intPtr(7)},
+				{ID: 3, QuestionID: intPtr(questionID), ParentID: intPtr(1)},
+				{ID: 4, QuestionID: intPtr(questionID)},
+			}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Questions: mockQuestions, Answers: mockAnswers, Comments: mockComments})
+
+	req := httptest.NewRequest(http.MethodGet, "/questions/1?include=comments", nil)
+	w := httptest.NewRecorder()
+
+	h.GetQuestion(w, routed(t, "GET /questions/{id}", req))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response dto.QuestionWithAnswersResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(response.Comments) != 2 || response.Comments[0].ID != 1 || response.Comments[1].ID != 4 {
+		t.Fatalf("expected top-level comments 1 and 4, got %+v", response.Comments)
+	}
+
+	if len(response.Comments[0].Replies) != 1 || response.Comments[0].Replies[0].ID != 3 {
+		t.Errorf("expected reply 3 under comment 1, got %+v", response.Comments[0].Replies)
+	}
+
+	if len(response.Answers) != 1 {
+		t.Fatalf("expected 1 answer, got %+v", response.Answers)
+	}
+
+	if len(response.Answers[0].Comments) != 1 || response.Answers[0].Comments[0].ID != 2 {
+		t.Errorf("expected comment 2 on the answer, got %+v", response.Answers[0].Comments)
+	}
+}
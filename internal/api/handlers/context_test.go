@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+// blockingQuestionService never finishes a call on its own, like one stuck on the database.
+type blockingQuestionService struct {
+	mockQuestionService
+	aborted chan error
+}
+
+func (m *blockingQuestionService) wait(ctx context.Context) error {
+	<-ctx.Done()
+	m.aborted <- ctx.Err()
+
+	return ctx.Err()
+}
+
+func (m *blockingQuestionService) Get(ctx context.Context, id int) (*models.Question, error) {
+	return nil, m.wait(ctx)
+}
+
+func (m *blockingQuestionService) List(ctx context.Context, opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error) {
+	return nil, nil, m.wait(ctx)
+}
+
+func TestHandlers_RequestContextAbortsQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		handler func(h *Handlers) http.HandlerFunc
+	}{
+		{name: "list questions", pattern: "GET /questions/{$}", path: "/questions/", handler: func(h *Handlers) http.HandlerFunc { return h.ListQuestions }},
+		{name: "get question", pattern: "GET /questions/{id}", path: "/questions/1", handler: func(h *Handlers) http.HandlerFunc { return h.GetQuestion }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" cancelled", func(t *testing.T) {
+			questions := &blockingQuestionService{aborted: make(chan error, 1)}
+			h := newTestHandlers(Deps{Questions: questions})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			req := routed(t, tt.pattern, httptest.NewRequest(http.MethodGet, tt.path, nil).WithContext(ctx))
+			w := httptest.NewRecorder()
+
+			done := make(chan struct{})
+			go func() {
+				tt.handler(h)(w, req)
+				close(done)
+			}()
+
+			cancel()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("handler did not return after the request was cancelled")
+			}
+
+			if err := <-questions.aborted; !errors.Is(err, context.Canceled) {
+				t.Errorf("expected service call to see context.Canceled, got %v", err)
+			}
+		})
+
+		t.Run(tt.name+" deadline", func(t *testing.T) {
+			questions := &blockingQuestionService{aborted: make(chan error, 1)}
+			h := newTestHandlers(Deps{Questions: questions})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			req := routed(t, tt.pattern, httptest.NewRequest(http.MethodGet, tt.path, nil).WithContext(ctx))
+			w := httptest.NewRecorder()
+
+			tt.handler(h)(w, req)
+
+			if err := <-questions.aborted; !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected service call to see context.DeadlineExceeded, got %v", err)
+			}
+
+			if w.Code != http.StatusInternalServerError {
+				t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+			}
+		})
+	}
+}
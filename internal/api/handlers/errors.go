@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/service"
+)
+
+var (
+	errQuestionNotFound = problem.NotFound("question_not_found", "Question not found")
+	errAnswerNotFound   = problem.NotFound("answer_not_found", "Answer not found")
+	errCommentNotFound  = problem.NotFound("comment_not_found", "Comment not found")
+	errUserNotFound     = problem.NotFound("user_not_found", "User not found")
+
+	errUserNotRegistered     = problem.Forbidden("user_not_registered", "User is not registered")
+	errUserAlreadyRegistered = problem.New(http.StatusConflict, "user_already_registered", "User already registered")
+	errNotAuthor             = problem.Forbidden("not_author", "Only the author can do this")
+	errNotDeleter            = problem.Forbidden("not_deleter", "Only the user who deleted it or an admin can restore it")
+	errNotAdmin              = problem.Forbidden("not_admin", "Only admins can do this")
+)
+
+// serviceError responds with the problem matching an error of the services.
+// Unexpected errors are logged with msg and args and hidden behind a 500.
+func (h *Handlers) serviceError(w http.ResponseWriter, r *http.Request, err error, msg string, args ...any) {
+	var invalid *service.ValidationError
+
+	switch {
+	case errors.As(err, &invalid):
+		fields := make([]problem.FieldError, len(invalid.Fields))
+		for i, f := range invalid.Fields {
+			fields[i] = problem.FieldError{Field: f.Field, Message: f.Message}
+		}
+		problem.Write(w, r, problem.Validation(fields...))
+	case errors.Is(err, service.ErrQuestionNotFound):
+		problem.Write(w, r, errQuestionNotFound)
+	case errors.Is(err, service.ErrAnswerNotFound):
+		problem.Write(w, r, errAnswerNotFound)
+	case errors.Is(err, service.ErrNotAuthor):
+		problem.Write(w, r, errNotAuthor)
+	case errors.Is(err, service.ErrNotDeleter):
+		problem.Write(w, r, errNotDeleter)
+	case errors.Is(err, service.ErrUserNotRegistered):
+		problem.Write(w, r, errUserNotRegistered)
+	case errors.Is(err, service.ErrNothingToUpdate):
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeValidationFailed, "Nothing to update"))
+	default:
+		h.logger(r).ErrorContext(r.Context(), msg, append([]any{"error", err}, args...)...)
+		problem.Write(w, r, problem.Internal())
+	}
+}
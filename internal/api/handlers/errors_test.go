@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"github.com/makson2134/go-qa-service/internal/service"
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+func TestServiceError(t *testing.T) {
+	h := newTestHandlers(Deps{})
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "validation", err: &service.ValidationError{Fields: []service.FieldError{{Field: "body", Message: "Body cannot be empty"}}}, expectedStatus: http.StatusBadRequest, expectedCode: problem.CodeValidationFailed},
+		{name: "nothing to update", err: service.ErrNothingToUpdate, expectedStatus: http.StatusBadRequest, expectedCode: problem.CodeValidationFailed},
+		{name: "question not found", err: fmt.Errorf("%w: %w", service.ErrQuestionNotFound, repository.ErrNotFound), expectedStatus: http.StatusNotFound, expectedCode: "question_not_found"},
+		{name: "answer not found", err: service.ErrAnswerNotFound, expectedStatus: http.StatusNotFound, expectedCode: "answer_not_found"},
+		{name: "not author", err: service.ErrNotAuthor, expectedStatus: http.StatusForbidden, expectedCode: "not_author"},
+		{name: "not deleter", err: service.ErrNotDeleter, expectedStatus: http.StatusForbidden, expectedCode: "not_deleter"},
+		{name: "unexpected", err: context.DeadlineExceeded, expectedStatus: http.StatusInternalServerError, expectedCode: problem.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			h.serviceError(w, httptest.NewRequest(http.MethodGet, "/questions/1", nil), tt.err, "failed")
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var body struct {
+				Code string `json:"code"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if body.Code != tt.expectedCode {
+				t.Errorf("expected code %q, got %q", tt.expectedCode, body.Code)
+			}
+		})
+	}
+}
+
+func TestServiceErrorUsesRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	reqLog := slog.New(slog.NewJSONHandler(&buf, nil)).With("request_id", "req-42")
+
+	h := newTestHandlers(Deps{})
+
+	req := httptest.NewRequest(http.MethodGet, "/questions/1", nil)
+	req = req.WithContext(pkg.WithLogger(req.Context(), reqLog))
+	h.serviceError(httptest.NewRecorder(), req, context.DeadlineExceeded, "failed to get question", "id", 1)
+
+	if !strings.Contains(buf.String(), `"request_id":"req-42"`) || !strings.Contains(buf.String(), `"msg":"failed to get question"`) {
+		t.Errorf("expected the error to be logged with the request logger, got %s", buf.String())
+	}
+}
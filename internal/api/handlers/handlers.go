@@ -1,31 +1,108 @@
 package handlers
 
 import (
-	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strconv"
 
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/auth"
 	"github.com/makson2134/go-qa-service/internal/repository"
+	"github.com/makson2134/go-qa-service/internal/service"
+	"github.com/makson2134/go-qa-service/pkg"
 )
 
 type Handlers struct {
-	questions repository.QuestionRepository
-	answers   repository.AnswerRepository
+	questions service.QuestionService
+	answers   service.AnswerService
+	comments  repository.CommentRepository
+	users     repository.UserRepository
+	tags      repository.TagRepository
+	search    repository.SearchRepository
+	trash     repository.TrashRepository
+	auditLog  repository.AuditRepository
+	webhooks  repository.WebhookRepository
+	readiness ReadinessChecker
 	log       *slog.Logger
 }
 
-func New(questions repository.QuestionRepository, answers repository.AnswerRepository, log *slog.Logger) *Handlers {
+// Deps are the services and repositories the handlers use.
+type Deps struct {
+	Questions service.QuestionService
+	Answers   service.AnswerService
+	Comments  repository.CommentRepository
+	Users     repository.UserRepository
+	Tags      repository.TagRepository
+	Search    repository.SearchRepository
+	Trash     repository.TrashRepository
+	AuditLog  repository.AuditRepository
+	Webhooks  repository.WebhookRepository
+	Readiness ReadinessChecker
+}
+
+func New(deps Deps, log *slog.Logger) *Handlers {
 	return &Handlers{
-		questions: questions,
-		answers:   answers,
+		questions: deps.Questions,
+		answers:   deps.Answers,
+		comments:  deps.Comments,
+		users:     deps.Users,
+		tags:      deps.Tags,
+		search:    deps.Search,
+		trash:     deps.Trash,
+		auditLog:  deps.AuditLog,
+		webhooks:  deps.Webhooks,
+		readiness: deps.Readiness,
 		log:       log,
 	}
 }
 
-func (h *Handlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(map[string]string{"status": "ok"}); err != nil {
-		h.log.Error("Failed to encode health check response", "error", err)
+// logger is the logger of the request, with its ID, or the default one outside of the middleware.
+func (h *Handlers) logger(r *http.Request) *slog.Logger {
+	if l, ok := pkg.LoggerFromContext(r.Context()); ok {
+		return l
+	}
+
+	return h.log
+}
+
+// currentUser responds with 401 when the request has no authenticated user.
+func currentUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	principal, ok := currentPrincipal(w, r)
+	return principal.Subject, ok
+}
+
+// adminUser is currentUser that also responds with 403 when the user isn't an admin.
+func adminUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	principal, ok := currentPrincipal(w, r)
+	if !ok {
+		return "", false
+	}
+
+	if !principal.Admin {
+		problem.Write(w, r, errNotAdmin)
+		return "", false
 	}
+
+	return principal.Subject, true
+}
+
+func currentPrincipal(w http.ResponseWriter, r *http.Request) (auth.Principal, bool) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, "Unauthorized"))
+		return auth.Principal{}, false
+	}
+
+	return principal, true
+}
+
+// pathID parses the numeric path parameter name, responding with 400 when it isn't one.
+func pathID(w http.ResponseWriter, r *http.Request, name, entity string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		problem.Write(w, r, problem.Invalid(name, "Invalid "+entity+" ID"))
+		return 0, false
+	}
+
+	return id, true
 }
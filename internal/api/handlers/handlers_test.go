@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+// newTestHandlers fills the dependencies a test doesn't set with mocks that have no behavior.
+func newTestHandlers(deps Deps) *Handlers {
+	if deps.Questions == nil {
+		deps.Questions = &mockQuestionService{}
+	}
+	if deps.Answers == nil {
+		deps.Answers = &mockAnswerService{}
+	}
+	if deps.Comments == nil {
+		deps.Comments = &mockCommentRepo{}
+	}
+	if deps.Users == nil {
+		deps.Users = &mockUserRepo{}
+	}
+	if deps.Tags == nil {
+		deps.Tags = &mockTagRepo{}
+	}
+	if deps.Search == nil {
+		deps.Search = &mockSearchRepo{}
+	}
+	if deps.Trash == nil {
+		deps.Trash = &mockTrashRepo{}
+	}
+	if deps.AuditLog == nil {
+		deps.AuditLog = &mockAuditRepo{}
+	}
+	if deps.Webhooks == nil {
+		deps.Webhooks = &mockWebhookRepo{}
+	}
+	if deps.Readiness == nil {
+		deps.Readiness = &mockReadiness{}
+	}
+
+	return New(deps, pkg.NewLogger("error", "json"))
+}
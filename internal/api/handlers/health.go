@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/makson2134/go-qa-service/internal/health"
+)
+
+type ReadinessChecker interface {
+	Check(ctx context.Context) health.Report
+}
+
+// Livez only tells that the process serves requests, dependencies are checked by Readyz.
+func (h *Handlers) Livez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]string{"status": "ok"}); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// Readyz responds with 503 when a dependency is unusable or the server is shutting down.
+func (h *Handlers) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.readiness.Check(r.Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusReady {
+		status = http.StatusServiceUnavailable
+
+		for name, result := range report.Checks {
+			if result.Err != nil {
+				h.logger(r).WarnContext(r.Context(), "readiness check failed", "check", name, "error", result.Err)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
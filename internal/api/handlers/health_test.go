@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/health"
+)
+
+type mockReadiness struct {
+	report health.Report
+}
+
+func (m *mockReadiness) Check(ctx context.Context) health.Report {
+	return m.report
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		report         health.Report
+		expectedStatus int
+	}{
+		{
+			name: "ready",
+			report: health.Report{Status: health.StatusReady, Checks: map[string]health.CheckResult{
+				"database":   {Status: health.StatusOK},
+				"migrations": {Status: health.StatusOK, Detail: int64(20260120100000)},
+			}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "database down",
+			report: health.Report{Status: health.StatusNotReady, Checks: map[string]health.CheckResult{
+				"database": {Status: health.StatusFail, Error: "unavailable", Err: errors.New("connection refused")},
+			}},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandlers(Deps{Readiness: &mockReadiness{report: tt.report}})
+
+			w := httptest.NewRecorder()
+			h.Readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var body struct {
+				Status string `json:"status"`
+				Checks map[string]struct {
+					Status string `json:"status"`
+					Error  string `json:"error"`
+				} `json:"checks"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if body.Status != tt.report.Status || len(body.Checks) != len(tt.report.Checks) {
+				t.Errorf("unexpected body: %+v", body)
+			}
+			for name, result := range tt.report.Checks {
+				if body.Checks[name].Status != result.Status || body.Checks[name].Error != result.Error {
+					t.Errorf("expected check %s %+v, got %+v", name, result, body.Checks[name])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+func parseLimit(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return defaultPageLimit, nil
+	}
+
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return 0, problem.Invalid("limit", "limit must be an integer between 1 and "+strconv.Itoa(maxPageLimit))
+	}
+
+	return limit, nil
+}
+
+// parseTimeParam returns nil when the parameter is absent.
+func parseTimeParam(r *http.Request, name string) (*time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, problem.Invalid(name, name+" must be an RFC 3339 timestamp")
+	}
+
+	return &t, nil
+}
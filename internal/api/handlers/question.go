@@ -2,134 +2,348 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
-	"strconv"
-	"strings"
 
 	"github.com/makson2134/go-qa-service/internal/api/dto"
-	"gorm.io/gorm"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/markdown"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
 )
 
+const excerptLength = 200
+
 func (h *Handlers) ListQuestions(w http.ResponseWriter, r *http.Request) {
-	questions, err := h.questions.List()
+	opts, err := parseListQuestionsOptions(r)
 	if err != nil {
-		h.log.Error("failed to list questions", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		problem.Write(w, r, err)
+		return
+	}
 
+	questions, next, err := h.questions.List(r.Context(), opts)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to list questions")
 		return
 	}
 
-	response := make([]dto.QuestionResponse, len(questions))
-	for i, q := range questions {
-		response[i] = dto.QuestionResponse{
-			ID:        q.ID,
-			Text:      q.Text,
-			CreatedAt: q.CreatedAt,
-		}
+	response := dto.QuestionListResponse{
+		Questions: make([]dto.QuestionSummaryResponse, len(questions)),
+	}
+	for i := range questions {
+		response.Questions[i] = newQuestionSummaryResponse(&questions[i])
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.Error("failed to encode response", "error", err)
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func parseListQuestionsOptions(r *http.Request) (repository.ListQuestionsOptions, error) {
+	var opts repository.ListQuestionsOptions
+	var err error
+	query := r.URL.Query()
+
+	if opts.Limit, err = parseLimit(r); err != nil {
+		return opts, err
+	}
+
+	opts.Sort = repository.QuestionSort(query.Get("sort"))
+	switch opts.Sort {
+	case "":
+		opts.Sort = repository.SortCreatedAsc
+	case repository.SortCreatedAsc, repository.SortCreatedDesc, repository.SortAnswersCount, repository.SortScore:
+	default:
+		return opts, problem.Invalid("sort", "sort must be one of created_at, -created_at, answers_count, score")
+	}
+
+	if token := query.Get("cursor"); token != "" {
+		opts.Cursor, err = repository.DecodeCursor(token)
+		if err != nil || opts.Cursor.Sort != string(opts.Sort) {
+			return opts, problem.Invalid("cursor", "cursor is invalid or does not match sort")
+		}
+	}
+
+	opts.Tags = query["tag"]
+
+	switch query.Get("tag_mode") {
+	case "", "all":
+	case "any":
+		opts.MatchAnyTag = true
+	default:
+		return opts, problem.Invalid("tag_mode", "tag_mode must be one of all, any")
+	}
+
+	if opts.CreatedAfter, err = parseTimeParam(r, "created_after"); err != nil {
+		return opts, err
 	}
+	if opts.CreatedBefore, err = parseTimeParam(r, "created_before"); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
 }
 
 func (h *Handlers) CreateQuestion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.registeredUser(w, r)
+	if !ok {
+		return
+	}
+
 	var req dto.CreateQuestionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		problem.Write(w, r, problem.InvalidBody())
 		return
 	}
 
-	if strings.TrimSpace(req.Text) == "" {
-		http.Error(w, "Text cannot be empty", http.StatusBadRequest)
+	question, err := h.questions.Create(r.Context(), userID, req.Title, req.Body, req.Tags)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to create question")
 		return
 	}
 
-	question, err := h.questions.Create(req.Text)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newQuestionResponse(question)); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func (h *Handlers) GetQuestion(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "question")
+	if !ok {
+		return
+	}
+
+	// Only the first page of answers is embedded, ListAnswers serves the next ones
+	answersOpts, err := parseAnswersPage(r)
 	if err != nil {
-		h.log.Error("failed to create question", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		problem.Write(w, r, err)
+		return
+	}
 
+	var includeComments bool
+	switch r.URL.Query().Get("include") {
+	case "":
+	case "comments":
+		includeComments = true
+	default:
+		problem.Write(w, r, problem.Invalid("include", "include must be comments"))
+		return
+	}
+
+	question, err := h.questions.Get(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to get question", "id", id)
 		return
 	}
 
-	response := dto.QuestionResponse{
-		ID:        question.ID,
-		Text:      question.Text,
-		CreatedAt: question.CreatedAt,
+	answers, next, err := h.answers.List(r.Context(), id, answersOpts)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to list answers", "question_id", id)
+		return
+	}
+
+	response := dto.QuestionWithAnswersResponse{
+		QuestionResponse: newQuestionResponse(question),
+		Answers:          make([]dto.AnswerResponse, len(answers)),
+		AnswersTotal:     question.AnswersCount,
+	}
+	for i := range answers {
+		response.Answers[i] = newAnswerResponse(&answers[i])
+	}
+	if next != nil {
+		response.AnswersNextURL = answersNextURL(id, answersOpts, next)
+	}
+
+	if includeComments {
+		comments, err := h.comments.ListQuestionComments(r.Context(), id)
+		if err != nil {
+			h.logger(r).ErrorContext(r.Context(), "failed to list question comments", "error", err, "id", id)
+			problem.Write(w, r, problem.Internal())
+
+			return
+		}
+
+		var questionComments []models.Comment
+		answerComments := make(map[int][]models.Comment)
+		for _, comment := range comments {
+			if comment.AnswerID != nil {
+				answerComments[*comment.AnswerID] = append(answerComments[*comment.AnswerID], comment)
+			} else {
+				questionComments = append(questionComments, comment)
+			}
+		}
+
+		response.Comments = commentThreads(questionComments)
+		for i := range response.Answers {
+			response.Answers[i].Comments = commentThreads(answerComments[response.Answers[i].ID])
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.Error("failed to encode response", "error", err)
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
 	}
 }
 
-func (h *Handlers) GetQuestion(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/questions/")
+// UpdateQuestion serves both PUT and PATCH. PUT replaces the question and responds with 422 when a field is missing,
+// PATCH only changes the fields present in the body.
+func (h *Handlers) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "question")
+	if !ok {
+		return
+	}
 
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+	userID, ok := currentUser(w, r)
+	if !ok {
 		return
 	}
 
-	question, err := h.questions.GetByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			http.Error(w, "Question not found", http.StatusNotFound)
+	var req dto.UpdateQuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.InvalidBody())
+		return
+	}
 
+	if r.Method == http.MethodPut {
+		if missing := missingQuestionFields(req); len(missing) > 0 {
+			problem.Write(w, r, problem.Unprocessable(missing...))
 			return
 		}
+	}
+
+	changes := repository.QuestionChanges{Title: req.Title, Body: req.Body, Tags: req.Tags}
 
-		h.log.Error("failed to get question", "error", err, "id", id)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	question, err := h.questions.Update(r.Context(), id, userID, changes)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to update question", "id", id)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newQuestionResponse(question)); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
 
+func (h *Handlers) ListQuestionRevisions(w http.ResponseWriter, r *http.Request) {
+	questionID, ok := pathID(w, r, "id", "question")
+	if !ok {
 		return
 	}
 
-	answers := make([]dto.AnswerResponse, len(question.Answers))
-	for i, a := range question.Answers {
-		answers[i] = dto.AnswerResponse{
-			ID:         a.ID,
-			QuestionID: a.QuestionID,
-			UserID:     a.UserID,
-			Text:       a.Text,
-			CreatedAt:  a.CreatedAt,
-		}
+	revisions, err := h.questions.ListRevisions(r.Context(), questionID)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to list question revisions", "question_id", questionID)
+		return
 	}
 
-	response := dto.QuestionWithAnswersResponse{
-		ID:        question.ID,
-		Text:      question.Text,
-		CreatedAt: question.CreatedAt,
-		Answers:   answers,
+	response := make([]dto.RevisionResponse, len(revisions))
+	for i, rev := range revisions {
+		response[i] = dto.RevisionResponse{
+			ID:           rev.ID,
+			Title:        rev.Title,
+			BodyMarkdown: rev.Body,
+			BodyHTML:     markdown.ToHTML(rev.Body),
+			CreatedAt:    rev.CreatedAt,
+			ReplacedAt:   rev.ReplacedAt,
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.Error("failed to encode response", "error", err)
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
 	}
 }
 
+// DeleteQuestion moves the question to the trash along with its answers, see RestoreQuestion.
 func (h *Handlers) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/questions/")
+	id, ok := pathID(w, r, "id", "question")
+	if !ok {
+		return
+	}
 
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+	principal, ok := currentPrincipal(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.questions.Delete(r.Context(), id, principal.Subject, principal.Admin); err != nil {
+		h.serviceError(w, r, err, "failed to delete question", "id", id)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreQuestion handles POST /questions/{id}/restore.
+func (h *Handlers) RestoreQuestion(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "question")
+	if !ok {
 		return
 	}
 
-	if err := h.questions.Delete(id); err != nil {
-		h.log.Error("failed to delete question", "error", err, "id", id)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	principal, ok := currentPrincipal(w, r)
+	if !ok {
+		return
+	}
 
+	if err := h.questions.Restore(r.Context(), id, principal.Subject, principal.Admin); err != nil {
+		h.serviceError(w, r, err, "failed to restore question", "id", id)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func missingQuestionFields(req dto.UpdateQuestionRequest) []problem.FieldError {
+	var missing []problem.FieldError
+
+	if req.Title == nil {
+		missing = append(missing, problem.FieldError{Field: "title", Message: "Title is required"})
+	}
+
+	if req.Body == nil {
+		missing = append(missing, problem.FieldError{Field: "body", Message: "Body is required"})
+	}
+
+	if req.Tags == nil {
+		missing = append(missing, problem.FieldError{Field: "tags", Message: "Tags are required, [] for none"})
+	}
+
+	return missing
+}
+
+func newQuestionResponse(q *models.Question) dto.QuestionResponse {
+	return dto.QuestionResponse{
+		ID:               q.ID,
+		AuthorID:         q.AuthorID,
+		Title:            q.Title,
+		BodyMarkdown:     q.Body,
+		BodyHTML:         markdown.ToHTML(q.Body),
+		Score:            q.Score,
+		AcceptedAnswerID: q.AcceptedAnswerID,
+		Tags:             tagNames(q.Tags),
+		CreatedAt:        q.CreatedAt,
+		UpdatedAt:        q.UpdatedAt,
+	}
+}
+
+func newQuestionSummaryResponse(q *models.Question) dto.QuestionSummaryResponse {
+	return dto.QuestionSummaryResponse{
+		ID:               q.ID,
+		AuthorID:         q.AuthorID,
+		Title:            q.Title,
+		Excerpt:          markdown.Excerpt(q.Body, excerptLength),
+		Score:            q.Score,
+		AnswersCount:     q.AnswersCount,
+		AcceptedAnswerID: q.AcceptedAnswerID,
+		Tags:             tagNames(q.Tags),
+		CreatedAt:        q.CreatedAt,
+		UpdatedAt:        q.UpdatedAt,
+	}
+}
@@ -2,80 +2,641 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
 	"github.com/makson2134/go-qa-service/internal/models"
-	"github.com/makson2134/go-qa-service/pkg"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"github.com/makson2134/go-qa-service/internal/service"
 )
 
-type mockQuestionRepo struct {
-	getByIDFunc func(id int) (*models.Question, error)
+type mockQuestionService struct {
+	createFunc       func(authorID, title, body string, tags []string) (*models.Question, error)
+	getFunc          func(id int) (*models.Question, error)
+	listFunc         func(opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error)
+	updateFunc       func(id int, userID string, changes repository.QuestionChanges) (*models.Question, error)
+	acceptAnswerFunc func(id, answerID int, userID string) error
+	deleteFunc       func(id int, userID string, admin bool) error
+	restoreFunc      func(id int, userID string, admin bool) error
 }
 
-func (m *mockQuestionRepo) Create(text string) (*models.Question, error) {
+func (m *mockQuestionService) Create(ctx context.Context, authorID, title, body string, tags []string) (*models.Question, error) {
+	if m.createFunc != nil {
+		return m.createFunc(authorID, title, body, tags)
+	}
+	return nil, nil
+}
+
+func (m *mockQuestionService) Get(ctx context.Context, id int) (*models.Question, error) {
+	if m.getFunc != nil {
+		return m.getFunc(id)
+	}
 	return nil, nil
 }
 
-func (m *mockQuestionRepo) GetByID(id int) (*models.Question, error) {
-	if m.getByIDFunc != nil {
-		return m.getByIDFunc(id)
+func (m *mockQuestionService) List(ctx context.Context, opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error) {
+	if m.listFunc != nil {
+		return m.listFunc(opts)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockQuestionService) Update(ctx context.Context, id int, userID string, changes repository.QuestionChanges) (*models.Question, error) {
+	if m.updateFunc != nil {
+		return m.updateFunc(id, userID, changes)
 	}
 	return nil, nil
 }
 
-func (m *mockQuestionRepo) List() ([]models.Question, error) {
+func (m *mockQuestionService) ListRevisions(ctx context.Context, id int) ([]models.QuestionRevision, error) {
 	return nil, nil
 }
 
-func (m *mockQuestionRepo) Delete(id int) error {
+func (m *mockQuestionService) Vote(ctx context.Context, id int, userID string, value int) (int, error) {
+	return value, nil
+}
+
+func (m *mockQuestionService) AcceptAnswer(ctx context.Context, id, answerID int, userID string) error {
+	if m.acceptAnswerFunc != nil {
+		return m.acceptAnswerFunc(id, answerID, userID)
+	}
+	return nil
+}
+
+func (m *mockQuestionService) UnacceptAnswer(ctx context.Context, id int, userID string) error {
+	return nil
+}
+
+func (m *mockQuestionService) Delete(ctx context.Context, id int, userID string, admin bool) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id, userID, admin)
+	}
+	return nil
+}
+
+func (m *mockQuestionService) Restore(ctx context.Context, id int, userID string, admin bool) error {
+	if m.restoreFunc != nil {
+		return m.restoreFunc(id, userID, admin)
+	}
 	return nil
 }
 
-type mockAnswerRepo struct{}
+type mockAnswerService struct {
+	createFunc        func(questionID int, userID, body string) (*models.Answer, error)
+	listFunc          func(questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error)
+	updateFunc        func(id int, userID, body string) (*models.Answer, error)
+	listRevisionsFunc func(id int) ([]models.AnswerRevision, error)
+	voteFunc          func(id int, userID string, value int) (int, error)
+	deleteFunc        func(id int, userID string, admin bool) error
+}
 
-func (m *mockAnswerRepo) CreateAnswer(questionID int, userID, text string) (*models.Answer, error) {
+func (m *mockAnswerService) Create(ctx context.Context, questionID int, userID, body string) (*models.Answer, error) {
+	if m.createFunc != nil {
+		return m.createFunc(questionID, userID, body)
+	}
 	return nil, nil
 }
 
-func (m *mockAnswerRepo) GetAnswerByID(id int) (*models.Answer, error) {
+func (m *mockAnswerService) Get(ctx context.Context, id int) (*models.Answer, error) {
 	return nil, nil
 }
 
-func (m *mockAnswerRepo) DeleteAnswer(id int) error {
+func (m *mockAnswerService) List(ctx context.Context, questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error) {
+	if m.listFunc != nil {
+		return m.listFunc(questionID, opts)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockAnswerService) Update(ctx context.Context, id int, userID, body string) (*models.Answer, error) {
+	if m.updateFunc != nil {
+		return m.updateFunc(id, userID, body)
+	}
+	return nil, nil
+}
+
+func (m *mockAnswerService) ListRevisions(ctx context.Context, id int) ([]models.AnswerRevision, error) {
+	if m.listRevisionsFunc != nil {
+		return m.listRevisionsFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *mockAnswerService) Vote(ctx context.Context, id int, userID string, value int) (int, error) {
+	if m.voteFunc != nil {
+		return m.voteFunc(id, userID, value)
+	}
+	return value, nil
+}
+
+func (m *mockAnswerService) Delete(ctx context.Context, id int, userID string, admin bool) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id, userID, admin)
+	}
+	return nil
+}
+
+func (m *mockAnswerService) Restore(ctx context.Context, id int, userID string, admin bool) error {
 	return nil
 }
 
-func TestCreateQuestion_EmptyText(t *testing.T) {
-	logger := pkg.NewLogger("error", "json")
-	h := New(&mockQuestionRepo{}, &mockAnswerRepo{}, logger)
+func TestListQuestions_InvalidParams(t *testing.T) {
+	h := newTestHandlers(Deps{})
+
+	otherSortCursor := repository.Cursor{Sort: string(repository.SortCreatedDesc), ID: 1}.Encode()
 
 	tests := []struct {
-		name string
-		body map[string]string
+		name  string
+		query string
 	}{
+		{name: "limit not a number", query: "limit=abc"},
+		{name: "limit too small", query: "limit=0"},
+		{name: "limit too large", query: "limit=1000"},
+		{name: "unknown sort", query: "sort=text"},
+		{name: "malformed cursor", query: "cursor=not-a-cursor"},
+		{name: "cursor from another sort", query: "cursor=" + otherSortCursor},
+		{name: "bad created_after", query: "created_after=yesterday"},
+		{name: "bad created_before", query: "created_before=2025-13-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/questions/?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			h.ListQuestions(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestListQuestions_ReturnsNextCursor(t *testing.T) {
+	createdAt := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	var gotOpts repository.ListQuestionsOptions
+
+	mockQuestions := &mockQuestionService{
+		listFunc: func(opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error) {
+			gotOpts = opts
+			questions := []models.Question{{ID: 7, Title: "What is Go?", Body: "A **programming** language", CreatedAt: createdAt}}
+			next := &repository.Cursor{Sort: string(opts.Sort), ID: 7, CreatedAt: createdAt}
+
+			return questions, next, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Questions: mockQuestions})
+
+	req := httptest.NewRequest(http.MethodGet, "/questions/?limit=1&sort=-created_at&created_after=2025-01-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	h.ListQuestions(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if gotOpts.Limit != 1 || gotOpts.Sort != repository.SortCreatedDesc || gotOpts.CreatedAfter == nil {
+		t.Errorf("unexpected list options: %+v", gotOpts)
+	}
+
+	var response dto.QuestionListResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(response.Questions) != 1 {
+		t.Fatalf("expected 1 question, got %d", len(response.Questions))
+	}
+
+	if response.Questions[0].Excerpt != "A programming language" {
+		t.Errorf("expected plain text excerpt, got %q", response.Questions[0].Excerpt)
+	}
+
+	cursor, err := repository.DecodeCursor(response.NextCursor)
+	if err != nil {
+		t.Fatalf("failed to decode next_cursor: %v", err)
+	}
+
+	if cursor.ID != 7 || !cursor.CreatedAt.Equal(createdAt) {
+		t.Errorf("unexpected cursor: %+v", cursor)
+	}
+}
+
+func TestGetQuestion_EmbedsFirstPageOfAnswers(t *testing.T) {
+	acceptedID := 3
+	createdAt := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	mockQuestions := &mockQuestionService{
+		getFunc: func(id int) (*models.Question, error) {
+			return &models.Question{ID: id, Title: "What is Go?", AcceptedAnswerID: &acceptedID, AnswersCount: 3}, nil
+		},
+	}
+
+	tests := []struct {
+		query          string
+		expectedStatus int
+		expectedOpts   repository.ListAnswersOptions
+	}{
+		{query: "", expectedStatus: http.StatusOK, expectedOpts: repository.ListAnswersOptions{Limit: defaultPageLimit, Sort: repository.AnswerSortScore}},
+		{query: "?sort=newest&limit=2", expectedStatus: http.StatusOK, expectedOpts: repository.ListAnswersOptions{Limit: 2, Sort: repository.AnswerSortNewest}},
+		{query: "?sort=oldest", expectedStatus: http.StatusOK, expectedOpts: repository.ListAnswersOptions{Limit: defaultPageLimit, Sort: repository.AnswerSortOldest}},
+		{query: "?sort=votes", expectedStatus: http.StatusBadRequest},
+		{query: "?limit=0", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run("query"+tt.query, func(t *testing.T) {
+			var gotOpts repository.ListAnswersOptions
+			mockAnswers := &mockAnswerService{
+				listFunc: func(questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error) {
+					gotOpts = opts
+					answers := []models.Answer{
+						{ID: 3, QuestionID: questionID, Score: 1, IsAccepted: true},
+						{ID: 1, QuestionID: questionID, Score: 10, CreatedAt: createdAt},
+					}
+					return answers, &repository.Cursor{Sort: string(opts.Sort), ID: 1, CreatedAt: createdAt, Value: 10}, nil
+				},
+			}
+
+			h := newTestHandlers(Deps{Questions: mockQuestions, Answers: mockAnswers})
+
+			req := httptest.NewRequest(http.MethodGet, "/questions/1"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			h.GetQuestion(w, routed(t, "GET /questions/{id}", req))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if gotOpts != tt.expectedOpts {
+				t.Errorf("expected options %+v, got %+v", tt.expectedOpts, gotOpts)
+			}
+
+			var response dto.QuestionWithAnswersResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if len(response.Answers) != 2 || response.Answers[0].ID != acceptedID || !response.Answers[0].IsAccepted {
+				t.Errorf("expected the answers in the order of the page, got %+v", response.Answers)
+			}
+			if response.AnswersTotal != 3 {
+				t.Errorf("expected answers_total 3, got %d", response.AnswersTotal)
+			}
+
+			next, err := url.Parse(response.AnswersNextURL)
+			if err != nil || next.Path != "/questions/1/answers" {
+				t.Fatalf("unexpected answers_next_url %q", response.AnswersNextURL)
+			}
+			cursor, err := repository.DecodeCursor(next.Query().Get("cursor"))
+			if err != nil || cursor.ID != 1 || cursor.Sort != string(tt.expectedOpts.Sort) {
+				t.Errorf("unexpected cursor in answers_next_url: %+v, %v", cursor, err)
+			}
+			if next.Query().Get("sort") != string(tt.expectedOpts.Sort) || next.Query().Get("limit") != strconv.Itoa(tt.expectedOpts.Limit) {
+				t.Errorf("expected answers_next_url to keep sort and limit, got %q", response.AnswersNextURL)
+			}
+		})
+	}
+}
+
+func TestListAnswers(t *testing.T) {
+	createdAt := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	newestCursor := repository.Cursor{Sort: string(repository.AnswerSortNewest), ID: 5, CreatedAt: createdAt}.Encode()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCursor *repository.Cursor
+	}{
+		{name: "first page", query: "?sort=newest", expectedStatus: http.StatusOK},
+		{name: "next page", query: "?sort=newest&cursor=" + newestCursor, expectedStatus: http.StatusOK, expectedCursor: &repository.Cursor{Sort: "newest", ID: 5, CreatedAt: createdAt}},
+		{name: "cursor of another sort", query: "?cursor=" + newestCursor, expectedStatus: http.StatusBadRequest},
+		{name: "invalid cursor", query: "?cursor=abc", expectedStatus: http.StatusBadRequest},
+		{name: "unknown question", query: "?sort=oldest", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCursor *repository.Cursor
+			mockAnswers := &mockAnswerService{
+				listFunc: func(questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error) {
+					if opts.Sort == repository.AnswerSortOldest {
+						return nil, nil, service.ErrQuestionNotFound
+					}
+					gotCursor = opts.Cursor
+					return []models.Answer{{ID: 4, QuestionID: questionID, Body: "**Go**", CreatedAt: createdAt}}, &repository.Cursor{Sort: string(opts.Sort), ID: 4, CreatedAt: createdAt}, nil
+				},
+			}
+
+			h := newTestHandlers(Deps{Answers: mockAnswers})
+
+			req := httptest.NewRequest(http.MethodGet, "/questions/1/answers"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			h.ListAnswers(w, routed(t, "GET /questions/{id}/answers", req))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if (gotCursor == nil) != (tt.expectedCursor == nil) || (gotCursor != nil && (gotCursor.ID != tt.expectedCursor.ID || !gotCursor.CreatedAt.Equal(tt.expectedCursor.CreatedAt))) {
+				t.Errorf("expected cursor %+v, got %+v", tt.expectedCursor, gotCursor)
+			}
+
+			var response dto.AnswerPageResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if len(response.Answers) != 1 || response.Answers[0].BodyHTML == "" {
+				t.Errorf("expected full answers, got %+v", response.Answers)
+			}
+			if cursor, err := repository.DecodeCursor(response.NextCursor); err != nil || cursor.ID != 4 {
+				t.Errorf("unexpected next_cursor %q", response.NextCursor)
+			}
+		})
+	}
+}
+
+func TestQuestionHandlers_ProblemDetails(t *testing.T) {
+	mockQuestions := &mockQuestionService{
+		getFunc: func(id int) (*models.Question, error) {
+			return nil, service.ErrQuestionNotFound
+		},
+		createFunc: func(authorID, title, body string, tags []string) (*models.Question, error) {
+			return nil, &service.ValidationError{Fields: []service.FieldError{
+				{Field: "title", Message: "title cannot be empty"},
+				{Field: "body", Message: "Body cannot be empty"},
+				{Field: "tags", Message: "invalid tag: <b>"},
+			}}
+		},
+	}
+
+	h := newTestHandlers(Deps{Questions: mockQuestions})
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		req            *http.Request
+		expectedStatus int
+		expectedCode   string
+		expectedFields []string
+	}{
+		{
+			name:           "question not found",
+			handler:        h.GetQuestion,
+			req:            routed(t, "GET /questions/{id}", httptest.NewRequest(http.MethodGet, "/questions/1", nil)),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "question_not_found",
+		},
+		{
+			name:           "all invalid fields reported",
+			handler:        h.CreateQuestion,
+			req:            withUser(httptest.NewRequest(http.MethodPost, "/questions/", bytes.NewReader([]byte(`{"title": "", "body": "", "tags": ["<b>"]}`))), "user-123"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeValidationFailed,
+			expectedFields: []string{"title", "body", "tags"},
+		},
 		{
-			name: "empty string",
-			body: map[string]string{"text": ""},
+			name:           "invalid query parameter",
+			handler:        h.ListQuestions,
+			req:            httptest.NewRequest(http.MethodGet, "/questions/?limit=1000", nil),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeValidationFailed,
+			expectedFields: []string{"limit"},
 		},
 		{
-			name: "whitespace only",
-			body: map[string]string{"text": "   "},
+			name:           "invalid body",
+			handler:        h.UpdateQuestion,
+			req:            routed(t, "PATCH /questions/{id}", withUser(httptest.NewRequest(http.MethodPatch, "/questions/1", bytes.NewReader([]byte(`{`))), "user-123")),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeInvalidBody,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			bodyBytes, _ := json.Marshal(tt.body)
-			req := httptest.NewRequest(http.MethodPost, "/questions/", bytes.NewReader(bodyBytes))
 			w := httptest.NewRecorder()
 
-			h.CreateQuestion(w, req)
+			tt.handler(w, tt.req)
 
-			if w.Code != http.StatusBadRequest {
-				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != problem.ContentType {
+				t.Errorf("expected content type %q, got %q", problem.ContentType, ct)
+			}
+
+			var body struct {
+				Code   string               `json:"code"`
+				Errors []problem.FieldError `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if body.Code != tt.expectedCode {
+				t.Errorf("expected code %q, got %q", tt.expectedCode, body.Code)
+			}
+
+			fields := make([]string, len(body.Errors))
+			for i, e := range body.Errors {
+				fields[i] = e.Field
+			}
+			if !slices.Equal(fields, tt.expectedFields) && len(fields)+len(tt.expectedFields) > 0 {
+				t.Errorf("expected invalid fields %v, got %v", tt.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestUpdateQuestion_UserFromToken(t *testing.T) {
+	var gotUserID string
+	mockQuestions := &mockQuestionService{
+		updateFunc: func(id int, userID string, changes repository.QuestionChanges) (*models.Question, error) {
+			gotUserID = userID
+			if userID != "author" {
+				return nil, service.ErrNotAuthor
+			}
+			return &models.Question{ID: id, AuthorID: &userID, Title: *changes.Title, Body: "Details"}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Questions: mockQuestions})
+
+	tests := []struct {
+		name         string
+		userID       string
+		expectedCode int
+	}{
+		{name: "another user", userID: "intruder", expectedCode: http.StatusForbidden},
+		{name: "author", userID: "author", expectedCode: http.StatusOK},
+		{name: "unauthenticated", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUserID = ""
+			req := httptest.NewRequest(http.MethodPatch, "/questions/1", bytes.NewReader([]byte(`{"title": "What is Go?"}`)))
+			if tt.userID != "" {
+				req = withUser(req, tt.userID)
+			}
+			w := httptest.NewRecorder()
+
+			h.UpdateQuestion(w, routed(t, "PATCH /questions/{id}", req))
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if gotUserID != tt.userID {
+				t.Errorf("expected update by %q, got %q", tt.userID, gotUserID)
+			}
+		})
+	}
+}
+
+func TestUpdateQuestion_PutRequiresAllFields(t *testing.T) {
+	var gotChanges repository.QuestionChanges
+	mockQuestions := &mockQuestionService{
+		updateFunc: func(id int, userID string, changes repository.QuestionChanges) (*models.Question, error) {
+			gotChanges = changes
+			return &models.Question{ID: id, AuthorID: &userID, Title: "What is Go?", Body: "Details"}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Questions: mockQuestions})
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedCode   int
+		expectedFields []string
+	}{
+		{name: "put with every field", method: http.MethodPut, body: `{"title": "What is Go?", "body": "Details", "tags": []}`, expectedCode: http.StatusOK},
+		{name: "put without body", method: http.MethodPut, body: `{"title": "What is Go?", "tags": ["go"]}`, expectedCode: http.StatusUnprocessableEntity, expectedFields: []string{"body"}},
+		{name: "put with null tags", method: http.MethodPut, body: `{"title": "What is Go?", "body": "Details", "tags": null}`, expectedCode: http.StatusUnprocessableEntity, expectedFields: []string{"tags"}},
+		{name: "empty put", method: http.MethodPut, body: `{}`, expectedCode: http.StatusUnprocessableEntity, expectedFields: []string{"title", "body", "tags"}},
+		{name: "patch of the title", method: http.MethodPatch, body: `{"title": "What is Go?"}`, expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotChanges = repository.QuestionChanges{}
+			req := withUser(httptest.NewRequest(tt.method, "/questions/1", strings.NewReader(tt.body)), "author")
+			w := httptest.NewRecorder()
+
+			h.UpdateQuestion(w, routed(t, tt.method+" /questions/{id}", req))
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body)
+			}
+
+			if tt.expectedCode != http.StatusUnprocessableEntity {
+				if gotChanges.Title == nil {
+					t.Error("expected the update to reach the service")
+				}
+				return
+			}
+
+			var body struct {
+				Code   string               `json:"code"`
+				Errors []problem.FieldError `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			fields := make([]string, len(body.Errors))
+			for i, e := range body.Errors {
+				fields[i] = e.Field
+			}
+			if body.Code != problem.CodeValidationFailed || !slices.Equal(fields, tt.expectedFields) {
+				t.Errorf("expected missing fields %v, got %q %v", tt.expectedFields, body.Code, fields)
+			}
+			if gotChanges.Title != nil {
+				t.Error("expected an incomplete put not to reach the service")
+			}
+		})
+	}
+}
+
+func TestDeleteQuestion(t *testing.T) {
+	var deletedBy string
+	mockQuestions := &mockQuestionService{
+		deleteFunc: func(id int, userID string, admin bool) error {
+			if id == 999 {
+				return service.ErrQuestionNotFound
+			}
+			if !admin && userID != "author" {
+				return service.ErrNotAuthor
+			}
+			deletedBy = userID
+			return nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Questions: mockQuestions})
+
+	tests := []struct {
+		name              string
+		path              string
+		userID            string
+		admin             bool
+		expectedCode      int
+		expectedDeletedBy string
+	}{
+		{name: "author", path: "/questions/1", userID: "author", expectedCode: http.StatusNoContent, expectedDeletedBy: "author"},
+		{name: "admin", path: "/questions/1", userID: "moderator", admin: true, expectedCode: http.StatusNoContent, expectedDeletedBy: "moderator"},
+		{name: "another user", path: "/questions/1", userID: "intruder", expectedCode: http.StatusForbidden},
+		{name: "missing question", path: "/questions/999", userID: "author", expectedCode: http.StatusNotFound},
+		{name: "invalid id", path: "/questions/abc", userID: "author", expectedCode: http.StatusBadRequest},
+		{name: "unauthenticated", path: "/questions/1", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletedBy = ""
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			switch {
+			case tt.admin:
+				req = withAdmin(req, tt.userID)
+			case tt.userID != "":
+				req = withUser(req, tt.userID)
+			}
+			w := httptest.NewRecorder()
+
+			h.DeleteQuestion(w, routed(t, "DELETE /questions/{id}", req))
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if deletedBy != tt.expectedDeletedBy {
+				t.Errorf("expected the question to be deleted by %q, got %q", tt.expectedDeletedBy, deletedBy)
 			}
 		})
 	}
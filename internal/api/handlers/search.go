@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/markdown"
+)
+
+func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		problem.Write(w, r, problem.Invalid("q", "Query cannot be empty"))
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	hits, err := h.search.Search(r.Context(), query, limit)
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to search", "error", err, "query", query)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := dto.SearchResponse{
+		Query:   query,
+		Results: make([]dto.SearchHitResponse, len(hits)),
+	}
+	for i, hit := range hits {
+		hitType := "question"
+		if hit.AnswerID != nil {
+			hitType = "answer"
+		}
+
+		response.Results[i] = dto.SearchHitResponse{
+			Type:       hitType,
+			QuestionID: hit.QuestionID,
+			AnswerID:   hit.AnswerID,
+			Rank:       hit.Rank,
+			Snippet:    markdown.Highlight(hit.Snippet),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/models"
+)
+
+type mockSearchRepo struct {
+	searchFunc func(query string, limit int) ([]models.SearchHit, error)
+}
+
+func (m *mockSearchRepo) Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	if m.searchFunc != nil {
+		return m.searchFunc(query, limit)
+	}
+	return nil, nil
+}
+
+func TestSearch_EmptyQuery(t *testing.T) {
+	h := newTestHandlers(Deps{})
+
+	for _, target := range []string{"/search", "/search?q=", "/search?q=%20%20"} {
+		t.Run(target, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			w := httptest.NewRecorder()
+
+			h.Search(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestSearch_ReturnsHits(t *testing.T) {
+	answerID := 42
+	var gotQuery string
+	var gotLimit int
+
+	mockSearch := &mockSearchRepo{
+		searchFunc: func(query string, limit int) ([]models.SearchHit, error) {
+			gotQuery, gotLimit = query, limit
+
+			return []models.SearchHit{
+				{QuestionID: 1, Rank: 0.9, Snippet: "What is <mark>Go</mark>?"},
+				{QuestionID: 1, AnswerID: &answerID, Rank: 0.5, Snippet: "<mark>Go</mark> is a language"},
+			}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Search: mockSearch})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=go&limit=5", nil)
+	w := httptest.NewRecorder()
+
+	h.Search(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if gotQuery != "go" || gotLimit != 5 {
+		t.Errorf("expected query %q with limit 5, got %q with limit %d", "go", gotQuery, gotLimit)
+	}
+
+	var response dto.SearchResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(response.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(response.Results))
+	}
+
+	if response.Results[0].Type != "question" || response.Results[0].AnswerID != nil {
+		t.Errorf("expected first hit to be a question, got %+v", response.Results[0])
+	}
+
+	if response.Results[1].Type != "answer" || response.Results[1].AnswerID == nil || *response.Results[1].AnswerID != answerID {
+		t.Errorf("expected second hit to be answer %d, got %+v", answerID, response.Results[1])
+	}
+}
+
+func TestSearch_EscapesSnippet(t *testing.T) {
+	mockSearch := &mockSearchRepo{
+		searchFunc: func(query string, limit int) ([]models.SearchHit, error) {
+			// ts_headline works on the raw Markdown, HTML in it comes back as is
+			return []models.SearchHit{
+				{QuestionID: 1, Rank: 0.9, Snippet: `<img src=x onerror=alert(1)> breaks my <mark>page</mark>`},
+			}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Search: mockSearch})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=page", nil)
+	w := httptest.NewRecorder()
+
+	h.Search(w, req)
+
+	var response dto.SearchResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(response.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(response.Results))
+	}
+
+	expected := "&lt;img src=x onerror=alert(1)&gt; breaks my <mark>page</mark>"
+	if response.Results[0].Snippet != expected {
+		t.Errorf("expected snippet %q, got %q", expected, response.Results[0].Snippet)
+	}
+}
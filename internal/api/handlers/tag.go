@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/models"
+)
+
+func (h *Handlers) ListTags(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	tags, err := h.tags.ListTags(r.Context(), limit)
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to list tags", "error", err)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := make([]dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		response[i] = dto.TagResponse{
+			Name:           tag.Name,
+			QuestionsCount: tag.QuestionsCount,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+
+	return names
+}
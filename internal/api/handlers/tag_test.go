@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+type mockTagRepo struct{}
+
+func (m *mockTagRepo) ListTags(ctx context.Context, limit int) ([]models.Tag, error) {
+	return nil, nil
+}
+
+func TestListQuestions_TagFilter(t *testing.T) {
+	var gotOpts repository.ListQuestionsOptions
+	mockQuestions := &mockQuestionService{
+		listFunc: func(opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error) {
+			gotOpts = opts
+			return nil, nil, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Questions: mockQuestions})
+
+	// tags are normalized by the service
+	req := httptest.NewRequest(http.MethodGet, "/questions/?tag=Go&tag=PostgreSQL&tag_mode=any", nil)
+	w := httptest.NewRecorder()
+
+	h.ListQuestions(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if !slices.Equal(gotOpts.Tags, []string{"Go", "PostgreSQL"}) || !gotOpts.MatchAnyTag {
+		t.Errorf("unexpected tag filter: %v, match any %v", gotOpts.Tags, gotOpts.MatchAnyTag)
+	}
+}
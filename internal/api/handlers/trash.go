@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/markdown"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+const (
+	trashQuestions = "questions"
+	trashAnswers   = "answers"
+)
+
+// ListTrash handles GET /trash?type=questions|answers, only for admins.
+// The most recently deleted come first, answers of deleted questions are not listed on their own.
+func (h *Handlers) ListTrash(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminUser(w, r); !ok {
+		return
+	}
+
+	kind := r.URL.Query().Get("type")
+	if kind == "" {
+		kind = trashQuestions
+	}
+	if kind != trashQuestions && kind != trashAnswers {
+		problem.Write(w, r, problem.Invalid("type", "type must be one of: questions, answers"))
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var cursor *repository.Cursor
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		cursor, err = repository.DecodeCursor(token)
+		if err != nil || cursor.Sort != kind {
+			problem.Write(w, r, problem.Invalid("cursor", "cursor is invalid or does not match type"))
+			return
+		}
+	}
+
+	response := dto.TrashListResponse{Items: []dto.TrashItemResponse{}}
+
+	var next *repository.Cursor
+	if kind == trashQuestions {
+		var questions []models.Question
+		questions, next, err = h.trash.ListDeletedQuestions(r.Context(), limit, cursor)
+		for i := range questions {
+			response.Items = append(response.Items, newTrashedQuestionResponse(&questions[i]))
+		}
+	} else {
+		var answers []models.Answer
+		answers, next, err = h.trash.ListDeletedAnswers(r.Context(), limit, cursor)
+		for i := range answers {
+			response.Items = append(response.Items, newTrashedAnswerResponse(&answers[i]))
+		}
+	}
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to list trash", "error", err, "type", kind)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	if next != nil {
+		next.Sort = kind
+		response.NextCursor = next.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func newTrashedQuestionResponse(q *models.Question) dto.TrashItemResponse {
+	return dto.TrashItemResponse{
+		Type:      "question",
+		ID:        q.ID,
+		Title:     q.Title,
+		AuthorID:  q.AuthorID,
+		Excerpt:   markdown.Excerpt(q.Body, excerptLength),
+		CreatedAt: q.CreatedAt,
+		DeletedAt: q.DeletedAt.Time,
+		DeletedBy: q.DeletedBy,
+	}
+}
+
+func newTrashedAnswerResponse(a *models.Answer) dto.TrashItemResponse {
+	return dto.TrashItemResponse{
+		Type:       "answer",
+		ID:         a.ID,
+		QuestionID: a.QuestionID,
+		AuthorID:   &a.UserID,
+		Excerpt:    markdown.Excerpt(a.Body, excerptLength),
+		CreatedAt:  a.CreatedAt,
+		DeletedAt:  a.DeletedAt.Time,
+		DeletedBy:  a.DeletedBy,
+	}
+}
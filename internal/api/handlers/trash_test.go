@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/auth"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"github.com/makson2134/go-qa-service/internal/service"
+	"gorm.io/gorm"
+)
+
+type mockTrashRepo struct {
+	listDeletedQuestionsFunc func(limit int, cursor *repository.Cursor) ([]models.Question, *repository.Cursor, error)
+	listDeletedAnswersFunc   func(limit int, cursor *repository.Cursor) ([]models.Answer, *repository.Cursor, error)
+}
+
+func (m *mockTrashRepo) ListDeletedQuestions(ctx context.Context, limit int, cursor *repository.Cursor) ([]models.Question, *repository.Cursor, error) {
+	if m.listDeletedQuestionsFunc != nil {
+		return m.listDeletedQuestionsFunc(limit, cursor)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockTrashRepo) ListDeletedAnswers(ctx context.Context, limit int, cursor *repository.Cursor) ([]models.Answer, *repository.Cursor, error) {
+	if m.listDeletedAnswersFunc != nil {
+		return m.listDeletedAnswersFunc(limit, cursor)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockTrashRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, int64, error) {
+	return 0, 0, nil
+}
+
+func withAdmin(req *http.Request, userID string) *http.Request {
+	return req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Subject: userID, Admin: true}))
+}
+
+func TestListTrash(t *testing.T) {
+	deletedAt := time.Date(2026, 1, 26, 10, 0, 0, 0, time.UTC)
+	deleter := "moderator"
+
+	var gotCursor *repository.Cursor
+	mockTrash := &mockTrashRepo{
+		listDeletedQuestionsFunc: func(limit int, cursor *repository.Cursor) ([]models.Question, *repository.Cursor, error) {
+			gotCursor = cursor
+			questions := []models.Question{
+				{ID: 2, Title: "Deleted", Body: "**Gone**", DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}, DeletedBy: &deleter},
+			}
+			return questions, &repository.Cursor{ID: 2, CreatedAt: deletedAt}, nil
+		},
+		listDeletedAnswersFunc: func(limit int, cursor *repository.Cursor) ([]models.Answer, *repository.Cursor, error) {
+			return []models.Answer{{ID: 7, QuestionID: 1, UserID: "author", DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}}}, nil, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Trash: mockTrash})
+
+	tests := []struct {
+		name         string
+		target       string
+		admin        bool
+		expectedCode int
+		expectedType string
+	}{
+		{name: "questions", target: "/trash", admin: true, expectedCode: http.StatusOK, expectedType: "question"},
+		{name: "answers", target: "/trash?type=answers", admin: true, expectedCode: http.StatusOK, expectedType: "answer"},
+		{name: "unknown type", target: "/trash?type=comments", admin: true, expectedCode: http.StatusBadRequest},
+		{name: "cursor of another type", target: "/trash?type=answers&cursor=" + (repository.Cursor{Sort: "questions", ID: 2}).Encode(), admin: true, expectedCode: http.StatusBadRequest},
+		{name: "not an admin", target: "/trash", expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.admin {
+				req = withAdmin(req, "moderator")
+			} else {
+				req = withUser(req, "user-123")
+			}
+			w := httptest.NewRecorder()
+
+			h.ListTrash(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body)
+			}
+
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response dto.TrashListResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if len(response.Items) != 1 || response.Items[0].Type != tt.expectedType || !response.Items[0].DeletedAt.Equal(deletedAt) {
+				t.Errorf("expected one deleted %s, got %+v", tt.expectedType, response.Items)
+			}
+		})
+	}
+
+	// The cursor of the questions page leads to the next page of questions
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/trash", nil), "moderator")
+	w := httptest.NewRecorder()
+	h.ListTrash(w, req)
+
+	var first dto.TrashListResponse
+	if err := json.NewDecoder(w.Body).Decode(&first); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	req = withAdmin(httptest.NewRequest(http.MethodGet, "/trash?type=questions&cursor="+first.NextCursor, nil), "moderator")
+	h.ListTrash(httptest.NewRecorder(), req)
+
+	if gotCursor == nil || gotCursor.ID != 2 || !gotCursor.CreatedAt.Equal(deletedAt) {
+		t.Errorf("expected cursor after question 2, got %+v", gotCursor)
+	}
+}
+
+func TestRestoreQuestion(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *http.Request
+		expectedCode  int
+		expectedAdmin bool
+	}{
+		{name: "user who deleted it", req: withUser(httptest.NewRequest(http.MethodPost, "/questions/1/restore", nil), "deleter"), expectedCode: http.StatusNoContent},
+		{name: "admin", req: withAdmin(httptest.NewRequest(http.MethodPost, "/questions/1/restore", nil), "moderator"), expectedCode: http.StatusNoContent, expectedAdmin: true},
+		{name: "someone else", req: withUser(httptest.NewRequest(http.MethodPost, "/questions/1/restore", nil), "author"), expectedCode: http.StatusForbidden},
+		{name: "not in the trash", req: withUser(httptest.NewRequest(http.MethodPost, "/questions/999/restore", nil), "deleter"), expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAdmin bool
+			mockQuestions := &mockQuestionService{
+				restoreFunc: func(id int, userID string, admin bool) error {
+					gotAdmin = admin
+					if id == 999 {
+						return service.ErrQuestionNotFound
+					}
+					if userID != "deleter" && !admin {
+						return service.ErrNotDeleter
+					}
+					return nil
+				},
+			}
+
+			h := newTestHandlers(Deps{Questions: mockQuestions})
+
+			w := httptest.NewRecorder()
+			h.RestoreQuestion(w, routed(t, "POST /questions/{id}/restore", tt.req))
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if gotAdmin != tt.expectedAdmin {
+				t.Errorf("expected admin %v, got %v", tt.expectedAdmin, gotAdmin)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+const (
+	maxDisplayNameLength = 100
+	maxBioLength         = 1000
+)
+
+// RegisterUser creates the profile of the authenticated user, the token subject becomes the user ID.
+func (h *Handlers) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.RegisterUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.InvalidBody())
+		return
+	}
+
+	req.DisplayName = strings.TrimSpace(req.DisplayName)
+	if req.DisplayName == "" {
+		problem.Write(w, r, problem.Invalid("display_name", "DisplayName cannot be empty"))
+		return
+	}
+
+	if utf8.RuneCountInString(req.DisplayName) > maxDisplayNameLength {
+		problem.Write(w, r, problem.Invalid("display_name", "DisplayName is too long"))
+		return
+	}
+
+	if utf8.RuneCountInString(req.Bio) > maxBioLength {
+		problem.Write(w, r, problem.Invalid("bio", "Bio is too long"))
+		return
+	}
+
+	user, err := h.users.CreateUser(r.Context(), userID, req.DisplayName, req.Bio)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			problem.Write(w, r, errUserAlreadyRegistered)
+			return
+		}
+
+		h.logger(r).ErrorContext(r.Context(), "failed to create user", "error", err, "id", userID)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := dto.UserResponse{
+		ID:          user.ID,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		CreatedAt:   user.CreatedAt,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func (h *Handlers) GetUser(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	user, err := h.users.GetUserByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, errUserNotFound)
+			return
+		}
+
+		h.logger(r).ErrorContext(r.Context(), "failed to get user", "error", err, "id", id)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := dto.UserResponse{
+		ID:          user.ID,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		CreatedAt:   user.CreatedAt,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func (h *Handlers) ListUserAnswers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var cursor *repository.Cursor
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		cursor, err = repository.DecodeCursor(token)
+		if err != nil || cursor.Sort != "" {
+			problem.Write(w, r, problem.Invalid("cursor", "Invalid cursor"))
+			return
+		}
+	}
+
+	answers, next, err := h.users.ListAnswersByUser(r.Context(), userID, limit, cursor)
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to list user answers", "error", err, "user_id", userID)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := dto.AnswerListResponse{
+		Answers: make([]dto.AnswerSummaryResponse, len(answers)),
+	}
+	for i := range answers {
+		response.Answers[i] = newAnswerSummaryResponse(&answers[i])
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// ListUserQuestions supports the same query parameters as ListQuestions.
+func (h *Handlers) ListUserQuestions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	opts, err := parseListQuestionsOptions(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	opts.AuthorID = userID
+
+	questions, next, err := h.questions.List(r.Context(), opts)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to list user questions", "user_id", userID)
+		return
+	}
+
+	response := dto.QuestionListResponse{
+		Questions: make([]dto.QuestionSummaryResponse, len(questions)),
+	}
+	for i := range questions {
+		response.Questions[i] = newQuestionSummaryResponse(&questions[i])
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// pathUser checks that the user from /users/{id}/... exists.
+func (h *Handlers) pathUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := r.PathValue("id")
+
+	if _, err := h.users.GetUserByID(r.Context(), userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, errUserNotFound)
+			return "", false
+		}
+
+		h.logger(r).ErrorContext(r.Context(), "failed to check user existence", "error", err, "user_id", userID)
+		problem.Write(w, r, problem.Internal())
+
+		return "", false
+	}
+
+	return userID, true
+}
+
+// registeredUser is currentUser that also requires the user to have registered with POST /users.
+func (h *Handlers) registeredUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return "", false
+	}
+
+	if _, err := h.users.GetUserByID(r.Context(), userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, errUserNotRegistered)
+			return "", false
+		}
+
+		h.logger(r).ErrorContext(r.Context(), "failed to check user registration", "error", err, "user_id", userID)
+		problem.Write(w, r, problem.Internal())
+
+		return "", false
+	}
+
+	return userID, true
+}
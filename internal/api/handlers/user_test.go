@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+type mockUserRepo struct {
+	createUserFunc  func(id, displayName, bio string) (*models.User, error)
+	getUserByIDFunc func(id string) (*models.User, error)
+}
+
+func (m *mockUserRepo) CreateUser(ctx context.Context, id, displayName, bio string) (*models.User, error) {
+	if m.createUserFunc != nil {
+		return m.createUserFunc(id, displayName, bio)
+	}
+	return &models.User{ID: id, DisplayName: displayName, Bio: bio}, nil
+}
+
+func (m *mockUserRepo) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	if m.getUserByIDFunc != nil {
+		return m.getUserByIDFunc(id)
+	}
+	return &models.User{ID: id}, nil
+}
+
+func (m *mockUserRepo) ListAnswersByUser(ctx context.Context, userID string, limit int, cursor *repository.Cursor) ([]models.Answer, *repository.Cursor, error) {
+	return nil, nil, nil
+}
+
+func TestRegisterUser(t *testing.T) {
+	mockUsers := &mockUserRepo{
+		createUserFunc: func(id, displayName, bio string) (*models.User, error) {
+			if id == "existing" {
+				return nil, repository.ErrConflict
+			}
+			return &models.User{ID: id, DisplayName: displayName, Bio: bio}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Users: mockUsers})
+
+	tests := []struct {
+		name         string
+		userID       string
+		body         string
+		expectedCode int
+	}{
+		{name: "new user", userID: "new", body: `{"display_name": "Gopher"}`, expectedCode: http.StatusCreated},
+		{name: "already registered", userID: "existing", body: `{"display_name": "Gopher"}`, expectedCode: http.StatusConflict},
+		{name: "empty display name", userID: "new", body: `{"display_name": "  "}`, expectedCode: http.StatusBadRequest},
+		{name: "display name too long", userID: "new", body: `{"display_name": "` + strings.Repeat("a", 101) + `"}`, expectedCode: http.StatusBadRequest},
+		{name: "unauthenticated", body: `{"display_name": "Gopher"}`, expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader([]byte(tt.body)))
+			if tt.userID != "" {
+				req = withUser(req, tt.userID)
+			}
+			w := httptest.NewRecorder()
+
+			h.RegisterUser(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestCreateQuestion_UnregisteredUser(t *testing.T) {
+	mockUsers := &mockUserRepo{
+		getUserByIDFunc: func(id string) (*models.User, error) {
+			return nil, repository.ErrNotFound
+		},
+	}
+
+	h := newTestHandlers(Deps{Users: mockUsers})
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/questions/", bytes.NewReader([]byte(`{"title": "What is Go?", "body": "Details"}`))), "stranger")
+	w := httptest.NewRecorder()
+
+	h.CreateQuestion(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
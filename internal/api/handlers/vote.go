@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+)
+
+// VoteQuestion handles POST /questions/{id}/votes, voting again replaces the previous vote.
+func (h *Handlers) VoteQuestion(w http.ResponseWriter, r *http.Request) {
+	h.vote(w, r, "question", h.questions.Vote)
+}
+
+// VoteAnswer handles POST /answers/{id}/votes, voting again replaces the previous vote.
+func (h *Handlers) VoteAnswer(w http.ResponseWriter, r *http.Request) {
+	h.vote(w, r, "answer", h.answers.Vote)
+}
+
+type castVoteFunc func(ctx context.Context, id int, userID string, value int) (int, error)
+
+func (h *Handlers) vote(w http.ResponseWriter, r *http.Request, entity string, cast castVoteFunc) {
+	id, ok := pathID(w, r, "id", entity)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.registeredUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.VoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.InvalidBody())
+		return
+	}
+
+	score, err := cast(r.Context(), id, userID, req.Value)
+	if err != nil {
+		h.serviceError(w, r, err, "failed to vote", "entity", entity, "id", id, "user_id", userID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dto.VoteResponse{Value: req.Value, Score: score}); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
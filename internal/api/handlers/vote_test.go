@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/service"
+)
+
+func TestVoteAnswer(t *testing.T) {
+	mockAnswers := &mockAnswerService{
+		voteFunc: func(id int, userID string, value int) (int, error) {
+			if id == 404 {
+				return 0, service.ErrAnswerNotFound
+			}
+			return 10 + value, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Answers: mockAnswers})
+
+	tests := []struct {
+		name          string
+		target        string
+		body          string
+		expectedCode  int
+		expectedScore int
+	}{
+		{name: "upvote", target: "/answers/1/votes", body: `{"value": 1}`, expectedCode: http.StatusOK, expectedScore: 11},
+		{name: "downvote", target: "/answers/1/votes", body: `{"value": -1}`, expectedCode: http.StatusOK, expectedScore: 9},
+		{name: "invalid body", target: "/answers/1/votes", body: `{"value": "up"}`, expectedCode: http.StatusBadRequest},
+		{name: "unknown answer", target: "/answers/404/votes", body: `{"value": 1}`, expectedCode: http.StatusNotFound},
+		{name: "invalid id", target: "/answers/abc/votes", body: `{"value": 1}`, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodPost, tt.target, bytes.NewReader([]byte(tt.body))), "user-123")
+			w := httptest.NewRecorder()
+
+			h.VoteAnswer(w, routed(t, "POST /answers/{id}/votes", req))
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var response dto.VoteResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if response.Score != tt.expectedScore {
+				t.Errorf("expected score %d, got %d", tt.expectedScore, response.Score)
+			}
+		})
+	}
+}
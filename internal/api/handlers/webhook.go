@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"github.com/makson2134/go-qa-service/internal/webhook"
+)
+
+const maxWebhookURLLength = 2048
+
+var errWebhookNotFound = problem.NotFound("webhook_not_found", "Webhook not found")
+
+// CreateWebhook handles POST /webhooks, only for admins. The secret signing the deliveries
+// is only returned here.
+func (h *Handlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.InvalidBody())
+		return
+	}
+
+	req.URL = strings.TrimSpace(req.URL)
+	if !validWebhookURL(req.URL) {
+		problem.Write(w, r, problem.Invalid("url", "URL must be an absolute http or https URL"))
+		return
+	}
+
+	if len(req.Events) == 0 {
+		problem.Write(w, r, problem.Invalid("events", "Events cannot be empty"))
+		return
+	}
+
+	var events []string
+	for _, event := range req.Events {
+		if !slices.Contains(webhook.Events, event) {
+			problem.Write(w, r, problem.Invalid("events", "Events must be some of: "+strings.Join(webhook.Events, ", ")))
+			return
+		}
+
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	subscription, err := h.webhooks.CreateWebhook(r.Context(), req.URL, events, webhook.NewSecret(), userID)
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to create webhook", "error", err, "url", req.URL)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := newWebhookResponse(subscription)
+	response.Secret = subscription.Secret
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// ListWebhooks handles GET /webhooks, only for admins.
+func (h *Handlers) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminUser(w, r); !ok {
+		return
+	}
+
+	subscriptions, err := h.webhooks.ListWebhooks(r.Context())
+	if err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to list webhooks", "error", err)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := dto.WebhookListResponse{Webhooks: make([]dto.WebhookResponse, len(subscriptions))}
+	for i := range subscriptions {
+		response.Webhooks[i] = newWebhookResponse(&subscriptions[i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// DeleteWebhook handles DELETE /webhooks/{id}, only for admins. Pending deliveries are dropped.
+func (h *Handlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "webhook")
+	if !ok {
+		return
+	}
+
+	if _, ok := adminUser(w, r); !ok {
+		return
+	}
+
+	if err := h.webhooks.DeleteWebhook(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, errWebhookNotFound)
+			return
+		}
+
+		h.logger(r).ErrorContext(r.Context(), "failed to delete webhook", "error", err, "id", id)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles GET /webhooks/{id}/deliveries, only for admins. The newest deliveries come first.
+func (h *Handlers) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id", "webhook")
+	if !ok {
+		return
+	}
+
+	if _, ok := adminUser(w, r); !ok {
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var cursor *repository.Cursor
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		cursor, err = repository.DecodeCursor(token)
+		if err != nil {
+			problem.Write(w, r, problem.Invalid("cursor", "cursor is invalid"))
+			return
+		}
+	}
+
+	deliveries, next, err := h.webhooks.ListWebhookDeliveries(r.Context(), id, limit, cursor)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, errWebhookNotFound)
+			return
+		}
+
+		h.logger(r).ErrorContext(r.Context(), "failed to list webhook deliveries", "error", err, "id", id)
+		problem.Write(w, r, problem.Internal())
+
+		return
+	}
+
+	response := dto.WebhookDeliveryListResponse{Deliveries: make([]dto.WebhookDeliveryResponse, len(deliveries))}
+	for i := range deliveries {
+		response.Deliveries[i] = newWebhookDeliveryResponse(&deliveries[i])
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger(r).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+func validWebhookURL(raw string) bool {
+	if raw == "" || len(raw) > maxWebhookURLLength {
+		return false
+	}
+
+	u, err := url.Parse(raw)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func newWebhookResponse(s *models.WebhookSubscription) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    s.Events,
+		CreatedBy: s.CreatedBy,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func newWebhookDeliveryResponse(d *models.WebhookDelivery) dto.WebhookDeliveryResponse {
+	response := dto.WebhookDeliveryResponse{
+		ID:             d.ID,
+		Event:          d.Event,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if d.Status == models.DeliveryPending {
+		response.NextAttemptAt = &d.NextAttemptAt
+	}
+
+	return response
+}
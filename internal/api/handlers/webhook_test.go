@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/api/dto"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+type mockWebhookRepo struct {
+	createWebhookFunc         func(url string, events []string, secret, createdBy string) (*models.WebhookSubscription, error)
+	deleteWebhookFunc         func(id int) error
+	listWebhookDeliveriesFunc func(subscriptionID, limit int, cursor *repository.Cursor) ([]models.WebhookDelivery, *repository.Cursor, error)
+}
+
+func (m *mockWebhookRepo) CreateWebhook(ctx context.Context, url string, events []string, secret, createdBy string) (*models.WebhookSubscription, error) {
+	if m.createWebhookFunc != nil {
+		return m.createWebhookFunc(url, events, secret, createdBy)
+	}
+	return &models.WebhookSubscription{ID: 1, URL: url, Events: events, Secret: secret, CreatedBy: createdBy}, nil
+}
+
+func (m *mockWebhookRepo) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookRepo) DeleteWebhook(ctx context.Context, id int) error {
+	if m.deleteWebhookFunc != nil {
+		return m.deleteWebhookFunc(id)
+	}
+	return nil
+}
+
+func (m *mockWebhookRepo) ListWebhookDeliveries(ctx context.Context, subscriptionID, limit int, cursor *repository.Cursor) ([]models.WebhookDelivery, *repository.Cursor, error) {
+	if m.listWebhookDeliveriesFunc != nil {
+		return m.listWebhookDeliveriesFunc(subscriptionID, limit, cursor)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockWebhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookRepo) FinishDelivery(ctx context.Context, id int, result repository.DeliveryResult) error {
+	return nil
+}
+
+func TestCreateWebhook(t *testing.T) {
+	var gotEvents []string
+	var gotCreatedBy string
+	mockWebhooks := &mockWebhookRepo{
+		createWebhookFunc: func(url string, events []string, secret, createdBy string) (*models.WebhookSubscription, error) {
+			gotEvents, gotCreatedBy = events, createdBy
+			return &models.WebhookSubscription{ID: 3, URL: url, Events: events, Secret: secret, CreatedBy: createdBy}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Webhooks: mockWebhooks})
+
+	tests := []struct {
+		name         string
+		body         string
+		admin        bool
+		expectedCode int
+	}{
+		{name: "valid", body: `{"url":"https://tools.example.com/hooks","events":["question.created","answer.created","question.created"]}`, admin: true, expectedCode: http.StatusCreated},
+		{name: "relative url", body: `{"url":"/hooks","events":["question.created"]}`, admin: true, expectedCode: http.StatusBadRequest},
+		{name: "not http", body: `{"url":"ftp://tools.example.com/hooks","events":["question.created"]}`, admin: true, expectedCode: http.StatusBadRequest},
+		{name: "no events", body: `{"url":"https://tools.example.com/hooks","events":[]}`, admin: true, expectedCode: http.StatusBadRequest},
+		{name: "unknown event", body: `{"url":"https://tools.example.com/hooks","events":["user.created"]}`, admin: true, expectedCode: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, admin: true, expectedCode: http.StatusBadRequest},
+		{name: "not an admin", body: `{"url":"https://tools.example.com/hooks","events":["question.created"]}`, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.body))
+			if tt.admin {
+				req = withAdmin(req, "moderator")
+			} else {
+				req = withUser(req, "user-123")
+			}
+			w := httptest.NewRecorder()
+
+			h.CreateWebhook(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body)
+			}
+
+			if tt.expectedCode != http.StatusCreated {
+				return
+			}
+
+			var response dto.WebhookResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if len(response.Secret) != 64 {
+				t.Errorf("expected a generated secret in the response, got %q", response.Secret)
+			}
+			if !slices.Equal(gotEvents, []string{"question.created", "answer.created"}) || gotCreatedBy != "moderator" {
+				t.Errorf("expected deduplicated events created by the admin, got %v by %q", gotEvents, gotCreatedBy)
+			}
+		})
+	}
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	statusCode := http.StatusServiceUnavailable
+	nextAttempt := time.Date(2026, 2, 9, 10, 5, 0, 0, time.UTC)
+
+	var gotCursor *repository.Cursor
+	mockWebhooks := &mockWebhookRepo{
+		listWebhookDeliveriesFunc: func(subscriptionID, limit int, cursor *repository.Cursor) ([]models.WebhookDelivery, *repository.Cursor, error) {
+			if subscriptionID != 3 {
+				return nil, nil, repository.ErrNotFound
+			}
+
+			gotCursor = cursor
+			deliveries := []models.WebhookDelivery{
+				{ID: 8, Event: "answer.created", Payload: json.RawMessage(`{"id":12}`), Status: models.DeliveryPending, Attempts: 1, NextAttemptAt: nextAttempt, LastStatusCode: &statusCode},
+				{ID: 7, Event: "question.created", Payload: json.RawMessage(`{"id":11}`), Status: models.DeliveryDelivered, Attempts: 1, NextAttemptAt: nextAttempt},
+			}
+			return deliveries, &repository.Cursor{ID: 7}, nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Webhooks: mockWebhooks})
+
+	tests := []struct {
+		name         string
+		id           string
+		admin        bool
+		expectedCode int
+	}{
+		{name: "history", id: "3", admin: true, expectedCode: http.StatusOK},
+		{name: "missing webhook", id: "4", admin: true, expectedCode: http.StatusNotFound},
+		{name: "invalid id", id: "abc", admin: true, expectedCode: http.StatusBadRequest},
+		{name: "not an admin", id: "3", expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/"+tt.id+"/deliveries", nil)
+			req.SetPathValue("id", tt.id)
+			if tt.admin {
+				req = withAdmin(req, "moderator")
+			} else {
+				req = withUser(req, "user-123")
+			}
+			w := httptest.NewRecorder()
+
+			h.ListWebhookDeliveries(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body)
+			}
+
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response dto.WebhookDeliveryListResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if len(response.Deliveries) != 2 || response.NextCursor == "" {
+				t.Fatalf("expected 2 deliveries and a next cursor, got %+v", response)
+			}
+
+			pending, delivered := response.Deliveries[0], response.Deliveries[1]
+			if pending.NextAttemptAt == nil || !pending.NextAttemptAt.Equal(nextAttempt) || *pending.LastStatusCode != statusCode {
+				t.Errorf("expected the pending delivery with its next attempt and last status, got %+v", pending)
+			}
+			if delivered.NextAttemptAt != nil || string(delivered.Payload) != `{"id":11}` {
+				t.Errorf("expected the delivered payload without a next attempt, got %+v", delivered)
+			}
+		})
+	}
+
+	req := withAdmin(httptest.NewRequest(http.MethodGet, "/webhooks/3/deliveries?cursor="+(repository.Cursor{ID: 7}).Encode(), nil), "moderator")
+	req.SetPathValue("id", "3")
+	h.ListWebhookDeliveries(httptest.NewRecorder(), req)
+
+	if gotCursor == nil || gotCursor.ID != 7 {
+		t.Errorf("expected cursor after delivery 7, got %+v", gotCursor)
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	mockWebhooks := &mockWebhookRepo{
+		deleteWebhookFunc: func(id int) error {
+			if id != 3 {
+				return repository.ErrNotFound
+			}
+			return nil
+		},
+	}
+
+	h := newTestHandlers(Deps{Webhooks: mockWebhooks})
+
+	tests := []struct {
+		name         string
+		id           string
+		expectedCode int
+	}{
+		{name: "existing", id: "3", expectedCode: http.StatusNoContent},
+		{name: "missing", id: "4", expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withAdmin(httptest.NewRequest(http.MethodDelete, "/webhooks/"+tt.id, nil), "moderator")
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			h.DeleteWebhook(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/auth"
+	"github.com/makson2134/go-qa-service/internal/requestid"
+)
+
+// Audit puts the actor of the request into the context, for the changes it makes to be recorded with it.
+// It goes after RequestID and Auth or Anonymous.
+func Audit(clientIP func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := audit.Actor{ClientIP: clientIP(r)}
+			if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+				actor.UserID = principal.Subject
+			}
+			if id, ok := requestid.FromContext(r.Context()); ok {
+				actor.RequestID = id
+			}
+
+			next.ServeHTTP(w, r.WithContext(audit.WithActor(r.Context(), actor)))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/auth"
+	"github.com/makson2134/go-qa-service/internal/requestid"
+)
+
+func TestAudit(t *testing.T) {
+	clientIP := func(r *http.Request) string { return "203.0.113.7" }
+
+	tests := []struct {
+		name     string
+		user     string
+		expected audit.Actor
+	}{
+		{name: "user", user: "user-123", expected: audit.Actor{UserID: "user-123", RequestID: "req-1", ClientIP: "203.0.113.7"}},
+		{name: "anonymous", expected: audit.Actor{RequestID: "req-1", ClientIP: "203.0.113.7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got audit.Actor
+			var ok bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, ok = audit.ActorFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/questions/", nil)
+			ctx := requestid.WithID(req.Context(), "req-1")
+			if tt.user != "" {
+				ctx = auth.WithPrincipal(ctx, auth.Principal{Subject: tt.user})
+			}
+
+			Audit(clientIP)(next).ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+			if !ok || got != tt.expected {
+				t.Errorf("expected actor %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
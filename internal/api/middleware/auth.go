@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/auth"
+)
+
+// Auth puts the subject of a valid bearer token into the request context.
+// Requests without a token may only read, handlers decide what else needs a user.
+func Auth(verifier *auth.Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				if !isSafeMethod(r.Method) {
+					unauthorized(w, r, `Bearer`, "Authentication required")
+					return
+				}
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				unauthorized(w, r, `Bearer error="invalid_request"`, "Authorization header must use the Bearer scheme")
+				return
+			}
+
+			principal, err := verifier.Verify(strings.TrimSpace(token))
+			if err != nil {
+				unauthorized(w, r, `Bearer error="invalid_token"`, "Invalid or expired token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// Anonymous is the dev mode replacement for Auth: the user is taken from
+// the X-User-ID header as is, without any verification.
+func Anonymous(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject := strings.TrimSpace(r.Header.Get("X-User-ID"))
+		if subject == "" {
+			subject = "anonymous"
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{Subject: subject})))
+	})
+}
+
+// Admins marks the users with one of the given subjects as admins.
+// It goes after Auth or Anonymous, requests without a user are passed as is.
+func Admins(subjects []string) func(http.Handler) http.Handler {
+	admins := make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		admins[subject] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFromContext(r.Context())
+			if ok && admins[principal.Subject] {
+				principal.Admin = true
+				r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, challenge, detail string) {
+	w.Header().Set("WWW-Authenticate", challenge)
+	problem.Write(w, r, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, detail))
+}
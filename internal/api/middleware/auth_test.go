@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/makson2134/go-qa-service/internal/auth"
+)
+
+func TestAuth(t *testing.T) {
+	secret := []byte("test-secret")
+
+	verifier, err := auth.NewVerifier("HS256", secret, "", "")
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	validToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "user-123",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		expectedCode  int
+		expectedUser  string
+	}{
+		{name: "anonymous read", method: http.MethodGet, expectedCode: http.StatusOK},
+		{name: "anonymous write", method: http.MethodPost, expectedCode: http.StatusUnauthorized},
+		{name: "invalid token", method: http.MethodGet, authorization: "Bearer garbage", expectedCode: http.StatusUnauthorized},
+		{name: "not a bearer token", method: http.MethodPost, authorization: "Basic dXNlcjpwYXNz", expectedCode: http.StatusUnauthorized},
+		{name: "valid token", method: http.MethodPost, authorization: "Bearer " + validToken, expectedCode: http.StatusOK, expectedUser: "user-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+					gotUser = p.Subject
+				}
+			})
+
+			req := httptest.NewRequest(tt.method, "/questions/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			Auth(verifier)(next).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate header on 401")
+			}
+
+			if gotUser != tt.expectedUser {
+				t.Errorf("expected user %q, got %q", tt.expectedUser, gotUser)
+			}
+		})
+	}
+}
+
+func TestAnonymous(t *testing.T) {
+	var gotUser string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := auth.PrincipalFromContext(r.Context())
+		gotUser = p.Subject
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/questions/", nil)
+	req.Header.Set("X-User-ID", "dev-user")
+
+	Anonymous(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotUser != "dev-user" {
+		t.Errorf("expected user %q, got %q", "dev-user", gotUser)
+	}
+}
+
+func TestAdmins(t *testing.T) {
+	tests := []struct {
+		name          string
+		user          string
+		expectedAdmin bool
+	}{
+		{name: "admin", user: "moderator", expectedAdmin: true},
+		{name: "regular user", user: "user-123"},
+		{name: "no user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got auth.Principal
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = auth.PrincipalFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+			if tt.user != "" {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Subject: tt.user}))
+			}
+
+			Admins([]string{"moderator"})(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			if got.Subject != tt.user || got.Admin != tt.expectedAdmin {
+				t.Errorf("expected user %q with admin %v, got %+v", tt.user, tt.expectedAdmin, got)
+			}
+		})
+	}
+}
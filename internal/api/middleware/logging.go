@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/requestid"
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+// RequestID keeps the X-Request-ID of the request, or generates one when it is missing or malformed.
+// The ID is put into the context and sent back in the response header.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+	})
+}
+
+// AccessLog gives the request a logger with its ID, see pkg.LoggerFromContext,
+// and logs every completed request with it.
+func AccessLog(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			reqLog := log
+			if id, ok := requestid.FromContext(r.Context()); ok {
+				reqLog = log.With("request_id", id)
+			}
+
+			next.ServeHTTP(rec, r.WithContext(pkg.WithLogger(r.Context(), reqLog)))
+
+			reqLog.InfoContext(r.Context(), "request completed",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.status,
+				"bytes", rec.bytes,
+				"duration_ms", float64(time.Since(start).Microseconds())/1000,
+				"remote_addr", r.RemoteAddr,
+				"user_agent", r.UserAgent(),
+			)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/requestid"
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		keepsID   bool
+		setHeader bool
+	}{
+		{name: "from header", header: "req-42", setHeader: true, keepsID: true},
+		{name: "missing", keepsID: false},
+		{name: "malformed", header: "req 42", setHeader: true, keepsID: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctxID string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID, _ = requestid.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/questions/", nil)
+			if tt.setHeader {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if tt.keepsID && ctxID != tt.header {
+				t.Errorf("expected ID %q, got %q", tt.header, ctxID)
+			}
+			if !tt.keepsID && (ctxID == tt.header || !requestid.Valid(ctxID)) {
+				t.Errorf("expected a generated ID, got %q", ctxID)
+			}
+			if got := w.Header().Get(requestid.Header); got != ctxID {
+				t.Errorf("expected response header %q, got %q", ctxID, got)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	var hasLogger bool
+	handler := RequestID(AccessLog(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqLog *slog.Logger
+		reqLog, hasLogger = pkg.LoggerFromContext(r.Context())
+		if hasLogger {
+			reqLog.InfoContext(r.Context(), "handling")
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	})))
+
+	req := httptest.NewRequest(http.MethodPost, "/questions/", nil)
+	req.Header.Set(requestid.Header, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !hasLogger {
+		t.Fatal("expected a request logger in the context")
+	}
+
+	dec := json.NewDecoder(&buf)
+
+	var handlerLine map[string]any
+	if err := dec.Decode(&handlerLine); err != nil {
+		t.Fatalf("failed to decode handler log line: %v", err)
+	}
+	if handlerLine["request_id"] != "req-42" {
+		t.Errorf("expected request_id in the handler log line, got %v", handlerLine)
+	}
+
+	var accessLine map[string]any
+	if err := dec.Decode(&accessLine); err != nil {
+		t.Fatalf("failed to decode access log line: %v", err)
+	}
+
+	expected := map[string]any{
+		"msg":        "request completed",
+		"request_id": "req-42",
+		"method":     http.MethodPost,
+		"path":       "/questions/",
+		"status":     float64(http.StatusCreated),
+		"bytes":      float64(len("created")),
+	}
+	for key, value := range expected {
+		if accessLine[key] != value {
+			t.Errorf("expected %s %v, got %v", key, value, accessLine[key])
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// Metrics reports every request to o. route must map requests to a small set of names,
+// e.g. path templates without IDs, as each name becomes a separate series.
+func Metrics(o RequestObserver, route func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			o.ObserveRequest(r.Method, route(r), rec.status, time.Since(start))
+		})
+	}
+}
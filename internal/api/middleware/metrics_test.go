@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+type mockObserver struct {
+	requests []recordedRequest
+}
+
+func (m *mockObserver) ObserveRequest(method, route string, status int, duration time.Duration) {
+	m.requests = append(m.requests, recordedRequest{method: method, route: route, status: status})
+}
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		expectedStatus int
+	}{
+		{
+			name:           "implicit ok",
+			handler:        func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("ok")) },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "explicit status",
+			handler:        func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "status after body is ignored",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer := &mockObserver{}
+			route := func(r *http.Request) string { return "/questions/{id}" }
+
+			Metrics(observer, route)(tt.handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/questions/1", nil))
+
+			expected := recordedRequest{method: http.MethodGet, route: "/questions/{id}", status: tt.expectedStatus}
+			if len(observer.requests) != 1 || observer.requests[0] != expected {
+				t.Errorf("expected %+v, got %+v", expected, observer.requests)
+			}
+		})
+	}
+}
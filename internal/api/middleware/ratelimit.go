@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/internal/auth"
+	"github.com/makson2134/go-qa-service/internal/ratelimit"
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+// RateLimit limits reads (GET, HEAD, OPTIONS) and writes separately, with a bucket per authenticated
+// user or, for anonymous requests, per client IP. A disabled limit lets everything through.
+// Requests are let through when the store fails, so that a database hiccup doesn't take the API down.
+func RateLimit(store ratelimit.Store, reads, writes ratelimit.Limit, clientIP func(r *http.Request) string, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			class, limit := "read", reads
+			if !isSafeMethod(r.Method) {
+				class, limit = "write", writes
+			}
+
+			key := class + ":ip:" + clientIP(r)
+			if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+				key = class + ":user:" + principal.Subject
+			}
+
+			if take(w, r, store, key, limit, log) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// IPRateLimit limits all requests with a bucket per client IP. It goes before authentication,
+// so that requests with made up tokens are limited as well, with RateLimit behind it for each user.
+func IPRateLimit(store ratelimit.Store, limit ratelimit.Limit, clientIP func(r *http.Request) string, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if take(w, r, store, "ip:"+clientIP(r), limit, log) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// SkipPaths lets requests for paths, e.g. the probes, bypass mw.
+func SkipPaths(paths []string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limited := mw(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(paths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
+
+// take reports whether the request can go on, otherwise it has been answered with a 429.
+func take(w http.ResponseWriter, r *http.Request, store ratelimit.Store, key string, limit ratelimit.Limit, log *slog.Logger) bool {
+	if !limit.Enabled() {
+		return true
+	}
+
+	result, err := store.Take(r.Context(), key, limit)
+	if err != nil {
+		reqLog, ok := pkg.LoggerFromContext(r.Context())
+		if !ok {
+			reqLog = log
+		}
+		reqLog.ErrorContext(r.Context(), "failed to check rate limit", "error", err, "key", key)
+
+		return true
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", ceilSeconds(result.Reset))
+
+	if !result.Allowed {
+		w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+		problem.Write(w, r, problem.New(http.StatusTooManyRequests, problem.CodeRateLimited, "Too many requests, retry later"))
+
+		return false
+	}
+
+	return true
+}
+
+// ceilSeconds rounds up, so that a client waiting that long is sure to get a token.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// ClientIP returns the client address of a request. X-Forwarded-For is only believed when the request
+// comes from one of trustedProxies (IPs or CIDRs): the address is the rightmost one that is not a trusted proxy,
+// as everything to the left of it could have been set by the client.
+func ClientIP(trustedProxies []string) (func(r *http.Request) string, error) {
+	prefixes := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(proxy)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	trusted := func(addr netip.Addr) bool {
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr.Unmap()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(r *http.Request) string {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		addr, err := netip.ParseAddr(host)
+		if err != nil || !trusted(addr) {
+			return host
+		}
+
+		hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				break
+			}
+			addr = hop
+			if !trusted(hop) {
+				break
+			}
+		}
+
+		return addr.Unmap().String()
+	}, nil
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/auth"
+	"github.com/makson2134/go-qa-service/internal/ratelimit"
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+type mockStore struct {
+	keys   []string
+	result ratelimit.Result
+	err    error
+}
+
+func (m *mockStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	m.keys = append(m.keys, key)
+	return m.result, m.err
+}
+
+func TestRateLimit(t *testing.T) {
+	reads := ratelimit.Limit{Rate: 10, Burst: 20}
+	writes := ratelimit.Limit{Rate: 1, Burst: 5}
+	clientIP := func(r *http.Request) string { return "203.0.113.7" }
+
+	tests := []struct {
+		name           string
+		method         string
+		user           string
+		disableWrites  bool
+		store          *mockStore
+		expectedKey    string
+		expectedStatus int
+	}{
+		{
+			name:           "anonymous read",
+			method:         http.MethodGet,
+			store:          &mockStore{result: ratelimit.Result{Allowed: true, Limit: 20, Remaining: 19, Reset: 100 * time.Millisecond}},
+			expectedKey:    "read:ip:203.0.113.7",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "user write",
+			method:         http.MethodPost,
+			user:           "user1",
+			store:          &mockStore{result: ratelimit.Result{Allowed: true, Limit: 5, Remaining: 4, Reset: time.Second}},
+			expectedKey:    "write:user:user1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "limited",
+			method:         http.MethodPost,
+			user:           "user1",
+			store:          &mockStore{result: ratelimit.Result{Limit: 5, RetryAfter: 1500 * time.Millisecond, Reset: 5 * time.Second}},
+			expectedKey:    "write:user:user1",
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:           "store failure lets the request through",
+			method:         http.MethodPost,
+			user:           "user1",
+			store:          &mockStore{err: errors.New("connection refused")},
+			expectedKey:    "write:user:user1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "disabled limit",
+			method:         http.MethodPost,
+			disableWrites:  true,
+			store:          &mockStore{},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit := writes
+			if tt.disableWrites {
+				limit = ratelimit.Limit{}
+			}
+
+			called := false
+			handler := RateLimit(tt.store, reads, limit, clientIP, pkg.NewLogger("error", "json"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			req := httptest.NewRequest(tt.method, "/questions/", nil)
+			if tt.user != "" {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Subject: tt.user}))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if called != (tt.expectedStatus == http.StatusOK) {
+				t.Errorf("expected handler called %v, got %v", tt.expectedStatus == http.StatusOK, called)
+			}
+
+			if tt.expectedKey == "" {
+				if len(tt.store.keys) != 0 {
+					t.Errorf("expected the store not to be used, got %v", tt.store.keys)
+				}
+				return
+			}
+			if len(tt.store.keys) != 1 || tt.store.keys[0] != tt.expectedKey {
+				t.Errorf("expected key %q, got %v", tt.expectedKey, tt.store.keys)
+			}
+
+			if tt.store.err != nil {
+				return
+			}
+			result := tt.store.result
+			if w.Header().Get("RateLimit-Limit") == "" || w.Header().Get("RateLimit-Remaining") == "" || w.Header().Get("RateLimit-Reset") != ceilSeconds(result.Reset) {
+				t.Errorf("unexpected RateLimit headers: %v", w.Header())
+			}
+			if tt.expectedStatus == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "2" {
+				t.Errorf("expected Retry-After 2, got %q", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestIPRateLimit(t *testing.T) {
+	clientIP := func(r *http.Request) string { return "203.0.113.7" }
+	store := &mockStore{result: ratelimit.Result{Limit: 200, RetryAfter: time.Second, Reset: time.Minute}}
+
+	called := false
+	handler := IPRateLimit(store, ratelimit.Limit{Rate: 20, Burst: 200}, clientIP, pkg.NewLogger("error", "json"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	// The bucket of the IP is used whoever the token claims to be
+	req := httptest.NewRequest(http.MethodGet, "/questions/", nil)
+	req.Header.Set("Authorization", "Bearer made-up")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests || called {
+		t.Errorf("expected 429 without calling the handler, got %d, %v", w.Code, called)
+	}
+	if len(store.keys) != 1 || store.keys[0] != "ip:203.0.113.7" {
+		t.Errorf("expected key %q, got %v", "ip:203.0.113.7", store.keys)
+	}
+}
+
+func TestSkipPaths(t *testing.T) {
+	limited := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+	}
+	handler := SkipPaths([]string{"/livez", "/metrics"}, limited)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/livez", expectedStatus: http.StatusOK},
+		{path: "/metrics", expectedStatus: http.StatusOK},
+		{path: "/livez/", expectedStatus: http.StatusTooManyRequests},
+		{path: "/questions", expectedStatus: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	clientIP, err := ClientIP([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expectedIP   string
+	}{
+		{name: "direct", remoteAddr: "203.0.113.7:5000", expectedIP: "203.0.113.7"},
+		{name: "untrusted peer can't spoof", remoteAddr: "203.0.113.7:5000", forwardedFor: []string{"198.51.100.1"}, expectedIP: "203.0.113.7"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.5:5000", forwardedFor: []string{"198.51.100.1"}, expectedIP: "198.51.100.1"},
+		{name: "spoofed hop before the real client", remoteAddr: "10.0.0.5:5000", forwardedFor: []string{"1.1.1.1, 198.51.100.1"}, expectedIP: "198.51.100.1"},
+		{name: "chain of proxies", remoteAddr: "192.0.2.1:5000", forwardedFor: []string{"198.51.100.1", "10.1.2.3"}, expectedIP: "198.51.100.1"},
+		{name: "trusted proxy without header", remoteAddr: "10.0.0.5:5000", expectedIP: "10.0.0.5"},
+		{name: "ipv4 mapped peer", remoteAddr: "[::ffff:10.0.0.5]:5000", forwardedFor: []string{"198.51.100.1"}, expectedIP: "198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/questions/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			if got := clientIP(req); got != tt.expectedIP {
+				t.Errorf("expected %s, got %s", tt.expectedIP, got)
+			}
+		})
+	}
+
+	if _, err := ClientIP([]string{"not-an-ip"}); err == nil {
+		t.Error("expected an error for an invalid proxy")
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+// Recover turns a panic in a handler into a logged 500, instead of a dropped connection.
+// log is used when the request has no logger of its own.
+func Recover(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				// Deliberate abort of the response, net/http handles it
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				reqLog, ok := pkg.LoggerFromContext(r.Context())
+				if !ok {
+					reqLog = log
+				}
+				reqLog.ErrorContext(r.Context(), "panic while handling request", "panic", v, "stack", string(debug.Stack()))
+
+				// Part of the response is already sent, aborting lets the client see it is incomplete
+				if rec.wroteHeader {
+					panic(http.ErrAbortHandler)
+				}
+				problem.Write(rec, r, problem.Internal())
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
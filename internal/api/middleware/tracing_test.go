@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name           string
+		traceparent    string
+		status         int
+		expectedStatus codes.Code
+	}{
+		{name: "new trace", status: http.StatusOK, expectedStatus: codes.Unset},
+		{name: "continued trace", traceparent: "00-" + traceID + "-" + parentSpanID + "-01", status: http.StatusOK, expectedStatus: codes.Unset},
+		{name: "client error", status: http.StatusNotFound, expectedStatus: codes.Unset},
+		{name: "server error", status: http.StatusInternalServerError, expectedStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			route := func(r *http.Request) string { return "/questions/{id}" }
+
+			var handlerSpan trace.SpanContext
+			handler := Tracing(tp, route)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerSpan = trace.SpanContextFromContext(r.Context())
+				w.WriteHeader(tt.status)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/questions/1", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+			span := spans[0]
+
+			if span.Name != "GET /questions/{id}" {
+				t.Errorf("expected span name %q, got %q", "GET /questions/{id}", span.Name)
+			}
+			if span.SpanKind != trace.SpanKindServer {
+				t.Errorf("expected server span, got %v", span.SpanKind)
+			}
+			if span.Status.Code != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v", tt.expectedStatus, span.Status.Code)
+			}
+			if handlerSpan.SpanID() != span.SpanContext.SpanID() {
+				t.Error("expected the handler context to carry the server span")
+			}
+
+			if tt.traceparent != "" {
+				if span.SpanContext.TraceID().String() != traceID {
+					t.Errorf("expected trace ID %s, got %s", traceID, span.SpanContext.TraceID())
+				}
+				if span.Parent.SpanID().String() != parentSpanID {
+					t.Errorf("expected parent span %s, got %s", parentSpanID, span.Parent.SpanID())
+				}
+			} else if span.Parent.IsValid() {
+				t.Error("expected a root span")
+			}
+		})
+	}
+}
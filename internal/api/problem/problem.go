@@ -0,0 +1,135 @@
+// Package problem writes API errors as RFC 7807 problem details.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/makson2134/go-qa-service/internal/requestid"
+)
+
+const ContentType = "application/problem+json"
+
+// Codes shared by all endpoints, handlers add their own for specific cases.
+// Codes are part of the API, clients match on them instead of on the detail text.
+const (
+	CodeValidationFailed = "validation_failed"
+	CodeInvalidBody      = "invalid_body"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal_error"
+)
+
+// FieldError points at a body field or a query parameter that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an API error. Detail is shown to clients, so it must not contain internals.
+type Error struct {
+	Status int
+	Code   string
+	Detail string
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
+}
+
+func New(status int, code, detail string) *Error {
+	return &Error{Status: status, Code: code, Detail: detail}
+}
+
+// Validation reports all invalid fields at once.
+func Validation(fields ...FieldError) *Error {
+	detail := "Request has invalid fields"
+	if len(fields) == 1 {
+		detail = fields[0].Message
+	}
+
+	return &Error{
+		Status: http.StatusBadRequest,
+		Code:   CodeValidationFailed,
+		Detail: detail,
+		Fields: fields,
+	}
+}
+
+// Invalid is Validation for a single field.
+func Invalid(field, message string) *Error {
+	return Validation(FieldError{Field: field, Message: message})
+}
+
+// Unprocessable reports the fields a complete representation is missing, e.g. in a PUT.
+func Unprocessable(fields ...FieldError) *Error {
+	err := Validation(fields...)
+	err.Status = http.StatusUnprocessableEntity
+
+	return err
+}
+
+func InvalidBody() *Error {
+	return New(http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+}
+
+func NotFound(code, detail string) *Error {
+	return New(http.StatusNotFound, code, detail)
+}
+
+func Forbidden(code, detail string) *Error {
+	return New(http.StatusForbidden, code, detail)
+}
+
+// Internal hides the cause, it should be logged before responding.
+func Internal() *Error {
+	return New(http.StatusInternalServerError, CodeInternal, "Internal Server Error")
+}
+
+type response struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// Write responds with err as problem details. Errors that are not *Error are treated as internal.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		apiErr = Internal()
+	}
+
+	body := response{
+		Type:      "about:blank",
+		Title:     http.StatusText(apiErr.Status),
+		Status:    apiErr.Status,
+		Detail:    apiErr.Detail,
+		Instance:  r.URL.Path,
+		Code:      apiErr.Code,
+		Errors:    apiErr.Fields,
+		RequestID: RequestID(r),
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(apiErr.Status)
+	_ = json.NewEncoder(w).Encode(body) // The status is already sent, nothing else to do
+}
+
+// RequestID is the ID the RequestID middleware assigned to the request, if any.
+func RequestID(r *http.Request) string {
+	id, _ := requestid.FromContext(r.Context())
+	return id
+}
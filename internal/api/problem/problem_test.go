@@ -0,0 +1,61 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/requestid"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+		expectedFields int
+	}{
+		{name: "api error", err: NotFound("question_not_found", "Question not found"), expectedStatus: http.StatusNotFound, expectedCode: "question_not_found"},
+		{name: "validation", err: Validation(FieldError{Field: "title", Message: "required"}, FieldError{Field: "body", Message: "required"}), expectedStatus: http.StatusBadRequest, expectedCode: CodeValidationFailed, expectedFields: 2},
+		{name: "wrapped api error", err: errors.Join(errors.New("context"), Invalid("limit", "too big")), expectedStatus: http.StatusBadRequest, expectedCode: CodeValidationFailed, expectedFields: 1},
+		{name: "unknown error", err: errors.New("connection refused"), expectedStatus: http.StatusInternalServerError, expectedCode: CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/questions/1", nil)
+			req = req.WithContext(requestid.WithID(req.Context(), "req-42"))
+			w := httptest.NewRecorder()
+
+			Write(w, req, tt.err)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != ContentType {
+				t.Errorf("expected content type %q, got %q", ContentType, ct)
+			}
+
+			var body response
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if body.Code != tt.expectedCode || body.Status != tt.expectedStatus || len(body.Errors) != tt.expectedFields {
+				t.Errorf("unexpected body: %+v", body)
+			}
+
+			if body.Instance != "/questions/1" || body.RequestID != "req-42" || body.Title != http.StatusText(tt.expectedStatus) {
+				t.Errorf("unexpected body: %+v", body)
+			}
+
+			if tt.expectedCode == CodeInternal && body.Detail != "Internal Server Error" {
+				t.Errorf("expected internal details to be hidden, got %q", body.Detail)
+			}
+		})
+	}
+}
@@ -5,57 +5,126 @@ import (
 	"strings"
 
 	"github.com/makson2134/go-qa-service/internal/api/handlers"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
 )
 
-func SetupRoutes(h *handlers.Handlers) http.Handler {
-	mux := http.NewServeMux()
-
-	mux.HandleFunc("/health", h.HealthCheck)
-
-	mux.HandleFunc("/questions/", func(w http.ResponseWriter, r *http.Request) {
-		path := strings.TrimPrefix(r.URL.Path, "/questions/")
-
-		if path == "" {
-			switch r.Method {
-			case http.MethodGet:
-				h.ListQuestions(w, r)
-			case http.MethodPost:
-				h.CreateQuestion(w, r)
-			default:
-				http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-			}
-			return
-		}
-
-		if strings.HasSuffix(path, "/answers/") {
-			if r.Method == http.MethodPost {
-				h.CreateAnswer(w, r)
-			} else {
-				http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-			}
-			return
-		}
-
-		switch r.Method {
-		case http.MethodGet:
-			h.GetQuestion(w, r)
-		case http.MethodDelete:
-			h.DeleteQuestion(w, r)
-		default:
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	mux.HandleFunc("/answers/", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			h.GetAnswer(w, r)
-		case http.MethodDelete:
-			h.DeleteAnswer(w, r)
-		default:
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	return mux
+// Router dispatches requests by method and path pattern.
+type Router struct {
+	mux      *http.ServeMux
+	patterns map[string]bool
+}
+
+func SetupRoutes(h *handlers.Handlers) *Router {
+	rt := &Router{mux: http.NewServeMux(), patterns: make(map[string]bool)}
+
+	rt.handle("GET /livez", h.Livez)
+	rt.handle("GET /readyz", h.Readyz)
+	rt.handle("GET /health", h.Livez) // Kept for existing probes, same as /livez
+
+	rt.handle("GET /tags", h.ListTags)
+	rt.handle("GET /search", h.Search)
+
+	// Collections are served with and without the trailing slash, the API used to document them with it
+	rt.handle("GET /questions", h.ListQuestions)
+	rt.handle("GET /questions/{$}", h.ListQuestions)
+	rt.handle("POST /questions", h.CreateQuestion)
+	rt.handle("POST /questions/{$}", h.CreateQuestion)
+	rt.handle("GET /questions/{id}", h.GetQuestion)
+	rt.handle("PUT /questions/{id}", h.UpdateQuestion)
+	rt.handle("PATCH /questions/{id}", h.UpdateQuestion)
+	rt.handle("DELETE /questions/{id}", h.DeleteQuestion)
+	rt.handle("POST /questions/{id}/restore", h.RestoreQuestion)
+	rt.handle("GET /questions/{id}/answers", h.ListAnswers)
+	rt.handle("GET /questions/{id}/answers/{$}", h.ListAnswers)
+	rt.handle("POST /questions/{id}/answers", h.CreateAnswer)
+	rt.handle("POST /questions/{id}/answers/{$}", h.CreateAnswer)
+	rt.handle("POST /questions/{id}/accept/{answer_id}", h.AcceptAnswer)
+	rt.handle("DELETE /questions/{id}/accept", h.UnacceptAnswer)
+	rt.handle("POST /questions/{id}/votes", h.VoteQuestion)
+	rt.handle("GET /questions/{id}/comments", h.ListQuestionComments)
+	rt.handle("POST /questions/{id}/comments", h.CreateQuestionComment)
+	rt.handle("GET /questions/{id}/revisions", h.ListQuestionRevisions)
+
+	rt.handle("GET /answers/{id}", h.GetAnswer)
+	rt.handle("PATCH /answers/{id}", h.UpdateAnswer)
+	rt.handle("DELETE /answers/{id}", h.DeleteAnswer)
+	rt.handle("POST /answers/{id}/restore", h.RestoreAnswer)
+	rt.handle("POST /answers/{id}/votes", h.VoteAnswer)
+	rt.handle("GET /answers/{id}/comments", h.ListAnswerComments)
+	rt.handle("POST /answers/{id}/comments", h.CreateAnswerComment)
+	rt.handle("GET /answers/{id}/revisions", h.ListAnswerRevisions)
+
+	rt.handle("DELETE /comments/{id}", h.DeleteComment)
+
+	rt.handle("POST /users", h.RegisterUser)
+	rt.handle("POST /users/{$}", h.RegisterUser)
+	rt.handle("GET /users/{id}", h.GetUser)
+	rt.handle("GET /users/{id}/questions", h.ListUserQuestions)
+	rt.handle("GET /users/{id}/answers", h.ListUserAnswers)
+
+	rt.handle("GET /trash", h.ListTrash)
+	rt.handle("GET /audit", h.ListAudit)
+
+	rt.handle("POST /webhooks", h.CreateWebhook)
+	rt.handle("GET /webhooks", h.ListWebhooks)
+	rt.handle("DELETE /webhooks/{id}", h.DeleteWebhook)
+	rt.handle("GET /webhooks/{id}/deliveries", h.ListWebhookDeliveries)
+
+	return rt
+}
+
+func (rt *Router) handle(pattern string, handler http.HandlerFunc) {
+	rt.mux.HandleFunc(pattern, handler)
+	rt.patterns[pattern] = true
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := rt.mux.Handler(r); !rt.patterns[pattern] {
+		// The mux answers itself with a 404, or a 405 listing the allowed methods in Allow
+		rt.mux.ServeHTTP(&routeErrorWriter{ResponseWriter: w, r: r}, r)
+		return
+	}
+
+	rt.mux.ServeHTTP(w, r)
+}
+
+// Route names the route of the request by its path pattern, e.g. /questions/{id}/votes.
+// Requests that match no route are "unmatched", so that scanning random URLs doesn't create new names.
+func (rt *Router) Route(r *http.Request) string {
+	_, pattern := rt.mux.Handler(r)
+	if !rt.patterns[pattern] {
+		return "unmatched"
+	}
+
+	_, path, _ := strings.Cut(pattern, " ")
+	return strings.TrimSuffix(strings.TrimSuffix(path, "{$}"), "/")
+}
+
+// routeErrorWriter replaces the plain text 404 and 405 responses of http.ServeMux with problem details.
+type routeErrorWriter struct {
+	http.ResponseWriter
+	r        *http.Request
+	replaced bool
+}
+
+func (w *routeErrorWriter) WriteHeader(status int) {
+	switch status {
+	case http.StatusNotFound:
+		problem.Write(w.ResponseWriter, w.r, problem.NotFound(problem.CodeNotFound, "Route not found"))
+	case http.StatusMethodNotAllowed:
+		problem.Write(w.ResponseWriter, w.r, problem.New(http.StatusMethodNotAllowed, problem.CodeMethodNotAllowed, "Method Not Allowed"))
+	default:
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	w.replaced = true
+}
+
+func (w *routeErrorWriter) Write(b []byte) (int, error) {
+	if w.replaced {
+		return len(b), nil
+	}
+
+	return w.ResponseWriter.Write(b)
 }
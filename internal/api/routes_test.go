@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/api/handlers"
+	"github.com/makson2134/go-qa-service/internal/api/problem"
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+// Handlers without dependencies: the requests below are answered before any of them is used,
+// which is enough to tell whether the right handler got the request.
+func newTestRouter() *Router {
+	return SetupRoutes(handlers.New(handlers.Deps{}, pkg.NewLogger("error", "json")))
+}
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedCode   string
+		expectedAllow  string
+	}{
+		{name: "liveness", method: http.MethodGet, path: "/livez", expectedStatus: http.StatusOK},
+		{name: "legacy health", method: http.MethodGet, path: "/health", expectedStatus: http.StatusOK},
+		{name: "create question", method: http.MethodPost, path: "/questions", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "create question with trailing slash", method: http.MethodPost, path: "/questions/", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "question with invalid id", method: http.MethodGet, path: "/questions/abc", expectedStatus: http.StatusBadRequest, expectedCode: problem.CodeValidationFailed},
+		{name: "create answer", method: http.MethodPost, path: "/questions/1/answers", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "create answer with trailing slash", method: http.MethodPost, path: "/questions/1/answers/", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "accept answer with invalid answer id", method: http.MethodPost, path: "/questions/1/accept/abc", expectedStatus: http.StatusBadRequest, expectedCode: problem.CodeValidationFailed},
+		{name: "register user with trailing slash", method: http.MethodPost, path: "/users/", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "question with trailing slash", method: http.MethodGet, path: "/questions/1/", expectedStatus: http.StatusNotFound, expectedCode: problem.CodeNotFound},
+		{name: "votes with trailing slash", method: http.MethodPost, path: "/answers/1/votes/", expectedStatus: http.StatusNotFound, expectedCode: problem.CodeNotFound},
+		{name: "unknown subresource", method: http.MethodGet, path: "/questions/1/unknown", expectedStatus: http.StatusNotFound, expectedCode: problem.CodeNotFound},
+		{name: "subresource under extra segment", method: http.MethodPost, path: "/questions/1/foo/answers/", expectedStatus: http.StatusNotFound, expectedCode: problem.CodeNotFound},
+		{name: "accept without answer", method: http.MethodPost, path: "/questions/1/accept", expectedStatus: http.StatusMethodNotAllowed, expectedCode: problem.CodeMethodNotAllowed, expectedAllow: "DELETE"},
+		{name: "answers collection", method: http.MethodGet, path: "/answers", expectedStatus: http.StatusNotFound, expectedCode: problem.CodeNotFound},
+		{name: "unknown path", method: http.MethodGet, path: "/wp-login.php", expectedStatus: http.StatusNotFound, expectedCode: problem.CodeNotFound},
+		{name: "list answers with invalid sort", method: http.MethodGet, path: "/questions/1/answers?sort=votes", expectedStatus: http.StatusBadRequest, expectedCode: problem.CodeValidationFailed},
+		{name: "replace answers of a question", method: http.MethodPut, path: "/questions/1/answers", expectedStatus: http.StatusMethodNotAllowed, expectedCode: problem.CodeMethodNotAllowed, expectedAllow: "GET, HEAD, POST"},
+		{name: "delete tags", method: http.MethodDelete, path: "/tags", expectedStatus: http.StatusMethodNotAllowed, expectedCode: problem.CodeMethodNotAllowed, expectedAllow: "GET, HEAD"},
+		{name: "replace question list", method: http.MethodPut, path: "/questions/", expectedStatus: http.StatusMethodNotAllowed, expectedCode: problem.CodeMethodNotAllowed, expectedAllow: "GET, HEAD, POST"},
+		{name: "restore question without user", method: http.MethodPost, path: "/questions/1/restore", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "trash without user", method: http.MethodGet, path: "/trash", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "audit without user", method: http.MethodGet, path: "/audit", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "webhooks without user", method: http.MethodPost, path: "/webhooks", expectedStatus: http.StatusUnauthorized, expectedCode: problem.CodeUnauthorized},
+		{name: "put answer", method: http.MethodPut, path: "/answers/1", expectedStatus: http.StatusMethodNotAllowed, expectedCode: problem.CodeMethodNotAllowed, expectedAllow: "DELETE, GET, HEAD, PATCH"},
+	}
+
+	router := newTestRouter()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body)
+			}
+
+			if allow := w.Header().Get("Allow"); allow != tt.expectedAllow {
+				t.Errorf("expected Allow %q, got %q", tt.expectedAllow, allow)
+			}
+
+			if tt.expectedCode == "" {
+				return
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != problem.ContentType {
+				t.Errorf("expected content type %q, got %q", problem.ContentType, ct)
+			}
+
+			var body struct {
+				Status int    `json:"status"`
+				Code   string `json:"code"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Status != tt.expectedStatus || body.Code != tt.expectedCode {
+				t.Errorf("expected %d %s, got %+v", tt.expectedStatus, tt.expectedCode, body)
+			}
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{method: http.MethodGet, path: "/health", expected: "/health"},
+		{method: http.MethodGet, path: "/livez", expected: "/livez"},
+		{method: http.MethodGet, path: "/readyz", expected: "/readyz"},
+		{method: http.MethodGet, path: "/questions", expected: "/questions"},
+		{method: http.MethodGet, path: "/questions/", expected: "/questions"},
+		{method: http.MethodGet, path: "/questions/42", expected: "/questions/{id}"},
+		{method: http.MethodPost, path: "/questions/42/answers/", expected: "/questions/{id}/answers"},
+		{method: http.MethodGet, path: "/questions/42/answers", expected: "/questions/{id}/answers"},
+		{method: http.MethodPost, path: "/questions/42/accept/7", expected: "/questions/{id}/accept/{answer_id}"},
+		{method: http.MethodPost, path: "/questions/42/votes", expected: "/questions/{id}/votes"},
+		{method: http.MethodGet, path: "/answers/7/comments", expected: "/answers/{id}/comments"},
+		{method: http.MethodGet, path: "/answers/7/revisions", expected: "/answers/{id}/revisions"},
+		{method: http.MethodDelete, path: "/comments/3", expected: "/comments/{id}"},
+		{method: http.MethodPost, path: "/answers/7/restore", expected: "/answers/{id}/restore"},
+		{method: http.MethodGet, path: "/trash", expected: "/trash"},
+		{method: http.MethodGet, path: "/audit", expected: "/audit"},
+		{method: http.MethodGet, path: "/webhooks/3/deliveries", expected: "/webhooks/{id}/deliveries"},
+		{method: http.MethodDelete, path: "/webhooks/3", expected: "/webhooks/{id}"},
+		{method: http.MethodGet, path: "/users/auth0|123/questions", expected: "/users/{id}/questions"},
+		{method: http.MethodGet, path: "/answers", expected: "unmatched"},
+		{method: http.MethodGet, path: "/questions/42/unknown", expected: "unmatched"},
+		{method: http.MethodPost, path: "/questions/42/votes/1", expected: "unmatched"},
+		{method: http.MethodDelete, path: "/tags", expected: "unmatched"},
+		{method: http.MethodGet, path: "/wp-login.php", expected: "unmatched"},
+	}
+
+	router := newTestRouter()
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.URL.Path = tt.path
+
+			if got := router.Route(req); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
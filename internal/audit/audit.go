@@ -0,0 +1,52 @@
+// Package audit carries who is behind a change down to the repository, which records the change
+// in the audit log in the same transaction.
+package audit
+
+import "context"
+
+// Actions are "<entity type>.<past tense verb>".
+const (
+	QuestionCreated    = "question.created"
+	QuestionUpdated    = "question.updated"
+	QuestionDeleted    = "question.deleted"
+	QuestionRestored   = "question.restored"
+	QuestionVoted      = "question.voted"
+	QuestionAccepted   = "question.accepted" // An answer was accepted
+	QuestionUnaccepted = "question.unaccepted"
+	AnswerCreated      = "answer.created"
+	AnswerUpdated      = "answer.updated"
+	AnswerDeleted      = "answer.deleted"
+	AnswerRestored     = "answer.restored"
+	AnswerVoted        = "answer.voted"
+	CommentCreated     = "comment.created"
+	CommentDeleted     = "comment.deleted"
+	UserCreated        = "user.created"
+	WebhookCreated     = "webhook.created"
+	WebhookDeleted     = "webhook.deleted"
+)
+
+const (
+	EntityQuestion = "question"
+	EntityAnswer   = "answer"
+	EntityComment  = "comment"
+	EntityUser     = "user"
+	EntityWebhook  = "webhook"
+)
+
+// Actor is the request a change was made by. UserID is empty for anonymous requests.
+type Actor struct {
+	UserID    string
+	RequestID string
+	ClientIP  string
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	a, ok := ctx.Value(actorKey{}).(Actor)
+	return a, ok
+}
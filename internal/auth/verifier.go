@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Small clock skew between us and the token issuer is fine
+const leeway = 30 * time.Second
+
+type Verifier struct {
+	key    any
+	parser *jwt.Parser
+}
+
+// NewVerifier accepts a shared secret for HS256 and a PEM encoded public key for RS256.
+// Issuer and audience are only checked when not empty.
+func NewVerifier(algorithm string, key []byte, issuer, audience string) (*Verifier, error) {
+	var verifyKey any
+
+	switch algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if len(key) == 0 {
+			return nil, errors.New("HS256 secret is empty")
+		}
+		verifyKey = key
+	case jwt.SigningMethodRS256.Alg():
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RS256 public key: %w", err)
+		}
+		verifyKey = publicKey
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %q", algorithm)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{algorithm}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+
+	return &Verifier{key: verifyKey, parser: jwt.NewParser(opts...)}, nil
+}
+
+func (v *Verifier) Verify(tokenString string) (Principal, error) {
+	var claims jwt.RegisteredClaims
+
+	_, err := v.parser.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (any, error) {
+		return v.key, nil
+	})
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if claims.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return Principal{Subject: claims.Subject}, nil
+}
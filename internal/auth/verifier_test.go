@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("test-secret")
+
+func signHS256(t *testing.T, secret []byte, claims jwt.RegisteredClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return token
+}
+
+func validClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "user-123",
+		Issuer:    "qa-auth",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func TestVerifier_HS256(t *testing.T) {
+	verifier, err := NewVerifier("HS256", testSecret, "qa-auth", "")
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+
+	noSubject := validClaims()
+	noSubject.Subject = ""
+
+	otherIssuer := validClaims()
+	otherIssuer.Issuer = "someone-else"
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: signHS256(t, testSecret, validClaims())},
+		{name: "wrong secret", token: signHS256(t, []byte("other-secret"), validClaims()), wantErr: true},
+		{name: "expired", token: signHS256(t, testSecret, expired), wantErr: true},
+		{name: "no expiry", token: signHS256(t, testSecret, noExpiry), wantErr: true},
+		{name: "no subject", token: signHS256(t, testSecret, noSubject), wantErr: true},
+		{name: "other issuer", token: signHS256(t, testSecret, otherIssuer), wantErr: true},
+		{name: "garbage", token: "not.a.token", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(tt.token)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("expected ErrInvalidToken, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if principal.Subject != "user-123" {
+				t.Errorf("expected subject %q, got %q", "user-123", principal.Subject)
+			}
+		})
+	}
+}
+
+func TestVerifier_RS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	verifier, err := NewVerifier("RS256", publicKeyPEM, "", "")
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims()).SignedString(privateKey)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	if _, err := verifier.Verify(token); err != nil {
+		t.Errorf("expected RS256 token to be valid, got %v", err)
+	}
+
+	// An HS256 token signed with the public key must not pass as RS256
+	if _, err := verifier.Verify(signHS256(t, publicKeyPEM, validClaims())); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected algorithm confusion to be rejected, got %v", err)
+	}
+}
+
+func TestNewVerifier_UnsupportedAlgorithm(t *testing.T) {
+	if _, err := NewVerifier("none", testSecret, "", ""); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -10,17 +11,26 @@ import (
 )
 
 type Config struct {
-	Env      string         `yaml:"env"`
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	Log      LogConfig      `yaml:"log"`
+	Env       string          `yaml:"env"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Log       LogConfig       `yaml:"log"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Trash     TrashConfig     `yaml:"trash"`
+	Webhooks  WebhooksConfig  `yaml:"webhooks"`
 }
 
 type ServerConfig struct {
-	Port         string        `env:"PORT" env-required:"true"`
-	ReadTimeout  time.Duration `yaml:"read_timeout"`
-	WriteTimeout time.Duration `yaml:"write_timeout"`
-	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	Port             string        `env:"PORT" env-required:"true"`
+	ReadTimeout      time.Duration `yaml:"read_timeout"`
+	WriteTimeout     time.Duration `yaml:"write_timeout"`
+	IdleTimeout      time.Duration `yaml:"idle_timeout"`
+	DBTimeout        time.Duration `yaml:"db_timeout" env-default:"5s"`        // Deadline for the queries of one request, 0 disables it
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout" env-default:"2s"` // Deadline for all checks of /readyz
+	DrainDelay       time.Duration `yaml:"drain_delay" env-default:"5s"`       // How long /readyz fails before shutdown starts
 }
 
 type DatabaseConfig struct {
@@ -38,6 +48,67 @@ type LogConfig struct {
 	Format string `yaml:"format"`
 }
 
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled" env-default:"true"`
+	Path    string `yaml:"path" env-default:"/metrics"`
+	Port    string `yaml:"port"` // Serves metrics on a separate port, on the API port when empty
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"` // otlp, stdout or none
+	ServiceName string  `yaml:"service_name" env-default:"go-qa-service"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"` // Share of new traces that are recorded, incoming ones keep their decision
+}
+
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres" // Shared by all replicas
+)
+
+// RateLimitConfig limits all requests per client IP before authentication, then reads and writes
+// per user, or per client IP without a token. 0 per minute disables a limit.
+type RateLimitConfig struct {
+	Store           string   `yaml:"store" env-default:"memory"`
+	IPPerMinute     int      `yaml:"ip_per_minute" env-default:"1200"`
+	IPBurst         int      `yaml:"ip_burst" env-default:"200"`
+	ReadsPerMinute  int      `yaml:"reads_per_minute" env-default:"600"`
+	ReadBurst       int      `yaml:"read_burst" env-default:"100"`
+	WritesPerMinute int      `yaml:"writes_per_minute" env-default:"30"`
+	WriteBurst      int      `yaml:"write_burst" env-default:"10"`
+	TrustedProxies  []string `yaml:"trusted_proxies"` // IPs or CIDRs allowed to set X-Forwarded-For
+}
+
+// TrashConfig sets how long deleted questions and answers can be restored before they are purged.
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"` // 0 disables the purge on this replica
+}
+
+// WebhooksConfig sets how queued webhook deliveries are sent. Failed ones are retried after MinBackoff,
+// twice as long every next time up to MaxBackoff, until MaxAttempts.
+type WebhooksConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"5s"` // 0 disables sending on this replica
+	Timeout      time.Duration `yaml:"timeout" env-default:"10s"`
+	MaxAttempts  int           `yaml:"max_attempts" env-default:"10"`
+	MinBackoff   time.Duration `yaml:"min_backoff" env-default:"30s"`
+	MaxBackoff   time.Duration `yaml:"max_backoff" env-default:"6h"`
+}
+
+const (
+	AuthModeJWT       = "jwt"
+	AuthModeAnonymous = "anonymous" // Trusts X-User-ID header, only allowed for env: local
+)
+
+type AuthConfig struct {
+	Mode          string   `yaml:"mode" env-default:"jwt"`
+	Algorithm     string   `yaml:"algorithm" env-default:"HS256"`
+	SecretFile    string   `yaml:"secret_file" env-default:"/run/secrets/jwt-secret"`
+	PublicKeyFile string   `yaml:"public_key_file" env-default:"/run/secrets/jwt-public-key"`
+	Issuer        string   `yaml:"issuer"`
+	Audience      string   `yaml:"audience"`
+	Admins        []string `yaml:"admins" env:"AUTH_ADMINS" env-separator:","` // Subjects allowed to manage the trash, audit log and webhooks
+}
+
 func (d *DatabaseConfig) GetDSN() (string, error) {
 	passwordFile := "/run/secrets/db-password" // #nosec G101
 	data, err := os.ReadFile(passwordFile)
@@ -53,6 +124,21 @@ func (d *DatabaseConfig) GetDSN() (string, error) {
 	return dsn, nil
 }
 
+// GetKey reads the HS256 secret or the RS256 public key, depending on the algorithm.
+func (a *AuthConfig) GetKey() ([]byte, error) {
+	keyFile := a.SecretFile
+	if a.Algorithm == "RS256" {
+		keyFile = a.PublicKeyFile
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s key from secrets: %w", a.Algorithm, err)
+	}
+
+	return []byte(strings.TrimSpace(string(data))), nil
+}
+
 func Load(configPath string) (*Config, error) {
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("config file does not exist: %s", configPath)
@@ -64,5 +150,29 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
+	switch cfg.Auth.Mode {
+	case AuthModeJWT:
+	case AuthModeAnonymous:
+		if cfg.Env != "local" {
+			return nil, fmt.Errorf("auth mode %q is only allowed for env local", AuthModeAnonymous)
+		}
+	default:
+		return nil, fmt.Errorf("unknown auth mode: %q", cfg.Auth.Mode)
+	}
+
+	switch cfg.RateLimit.Store {
+	case RateLimitStoreMemory, RateLimitStorePostgres:
+	default:
+		return nil, fmt.Errorf("unknown rate limit store: %q", cfg.RateLimit.Store)
+	}
+
+	if cfg.Trash.Retention <= 0 {
+		return nil, fmt.Errorf("trash retention must be positive, got %s", cfg.Trash.Retention)
+	}
+
+	if cfg.Webhooks.Timeout <= 0 || cfg.Webhooks.MaxAttempts < 1 || cfg.Webhooks.MinBackoff <= 0 || cfg.Webhooks.MaxBackoff < cfg.Webhooks.MinBackoff {
+		return nil, errors.New("webhooks need a positive timeout, at least one attempt and max_backoff not below min_backoff")
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,121 @@
+// Package health runs the readiness checks of the service's dependencies.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// Check returns a detail worth reporting, e.g. a version, or an error when the dependency is unusable.
+type Check func(ctx context.Context) (any, error)
+
+type CheckResult struct {
+	Status     string  `json:"status"`
+	Detail     any     `json:"detail,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+
+	// Err is the cause of a failure. It is only logged, as it may reveal internals
+	Err error `json:"-"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker is ready when all its checks pass and it isn't draining.
+type Checker struct {
+	timeout  time.Duration
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+// New creates a checker giving all checks together timeout to complete.
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a check, it must not be called once the checker is in use.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Drain makes the checker not ready for good, so that load balancers stop sending traffic before shutdown.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Check runs all checks concurrently.
+func (c *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{Status: StatusReady, Checks: make(map[string]CheckResult, len(c.checks)+1)}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, nc := range c.checks {
+		wg.Go(func() {
+			result := run(ctx, nc.check)
+
+			mu.Lock()
+			report.Checks[nc.name] = result
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	shutdown := CheckResult{Status: StatusOK}
+	if c.draining.Load() {
+		shutdown = CheckResult{Status: StatusFail, Error: "shutting down"}
+	}
+	report.Checks["shutdown"] = shutdown
+
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusNotReady
+		}
+	}
+
+	return report
+}
+
+func run(ctx context.Context, check Check) CheckResult {
+	start := time.Now()
+	detail, err := check(ctx)
+	result := CheckResult{
+		Status:     StatusOK,
+		Detail:     detail,
+		DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Status = StatusFail
+		result.Err = err
+		result.Error = "unavailable"
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = "timed out"
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChecker(t *testing.T) {
+	ok := func(ctx context.Context) (any, error) { return int64(20260120100000), nil }
+	failing := func(ctx context.Context) (any, error) {
+		return nil, errors.New("dial tcp 10.0.0.3:5432: connection refused")
+	}
+	hanging := func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	tests := []struct {
+		name           string
+		checks         map[string]Check
+		drain          bool
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		{
+			name:           "all ok",
+			checks:         map[string]Check{"database": ok, "migrations": ok},
+			expectedStatus: StatusReady,
+			expectedChecks: map[string]string{"database": StatusOK, "migrations": StatusOK, "shutdown": StatusOK},
+		},
+		{
+			name:           "failing check",
+			checks:         map[string]Check{"database": failing, "migrations": ok},
+			expectedStatus: StatusNotReady,
+			expectedChecks: map[string]string{"database": StatusFail, "migrations": StatusOK, "shutdown": StatusOK},
+		},
+		{
+			name:           "timed out check",
+			checks:         map[string]Check{"database": hanging},
+			expectedStatus: StatusNotReady,
+			expectedChecks: map[string]string{"database": StatusFail, "shutdown": StatusOK},
+		},
+		{
+			name:           "draining",
+			checks:         map[string]Check{"database": ok},
+			drain:          true,
+			expectedStatus: StatusNotReady,
+			expectedChecks: map[string]string{"database": StatusOK, "shutdown": StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := New(50 * time.Millisecond)
+			for name, check := range tt.checks {
+				checker.Add(name, check)
+			}
+			if tt.drain {
+				checker.Drain()
+			}
+
+			report := checker.Check(context.Background())
+
+			if report.Status != tt.expectedStatus {
+				t.Errorf("expected status %q, got %q", tt.expectedStatus, report.Status)
+			}
+			if len(report.Checks) != len(tt.expectedChecks) {
+				t.Errorf("expected %d checks, got %d", len(tt.expectedChecks), len(report.Checks))
+			}
+			for name, status := range tt.expectedChecks {
+				if got := report.Checks[name].Status; got != status {
+					t.Errorf("expected %s to be %q, got %q", name, status, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckerHidesErrors(t *testing.T) {
+	cause := errors.New("dial tcp 10.0.0.3:5432: connection refused")
+
+	checker := New(time.Second)
+	checker.Add("database", func(ctx context.Context) (any, error) { return nil, cause })
+	checker.Add("slow", func(ctx context.Context) (any, error) { return nil, context.DeadlineExceeded })
+
+	report := checker.Check(context.Background())
+
+	if result := report.Checks["database"]; result.Error != "unavailable" || !errors.Is(result.Err, cause) {
+		t.Errorf("expected the cause to be kept out of the error message, got %+v", result)
+	}
+	if result := report.Checks["slow"]; result.Error != "timed out" {
+		t.Errorf("expected a timeout, got %+v", result)
+	}
+}
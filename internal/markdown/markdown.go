@@ -0,0 +1,67 @@
+// Package markdown renders user-written Markdown for the API.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	renderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// goldmark already drops raw HTML, the policy also strips dangerous links and attributes
+	htmlPolicy = newHTMLPolicy()
+	textPolicy = bluemonday.StrictPolicy()
+
+	// Only the tags wrapping the matches are turned back into markup
+	markTags = strings.NewReplacer("&lt;mark&gt;", "<mark>", "&lt;/mark&gt;", "</mark>")
+)
+
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	// Keeps the language of fenced code blocks for client-side highlighting
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+
+	return policy
+}
+
+// ToHTML renders Markdown to HTML that is safe to embed in a page.
+func ToHTML(source string) string {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		// Only happens when writing to the buffer fails
+		return html.EscapeString(source)
+	}
+
+	return htmlPolicy.Sanitize(buf.String())
+}
+
+// Highlight makes a search snippet with the matches wrapped in <mark> safe to embed in a page.
+// The rest of the snippet is escaped, HTML written in a question shows up as text.
+func Highlight(snippet string) string {
+	return markTags.Replace(html.EscapeString(snippet))
+}
+
+// Excerpt returns the rendered text without markup, cut to maxLen characters at a word boundary.
+func Excerpt(source string, maxLen int) string {
+	text := html.UnescapeString(textPolicy.Sanitize(ToHTML(source)))
+	text = strings.Join(strings.Fields(text), " ")
+
+	if utf8.RuneCountInString(text) <= maxLen {
+		return text
+	}
+
+	cut := []rune(text)[:maxLen]
+	if i := strings.LastIndexByte(string(cut), ' '); i > 0 {
+		return string(cut)[:i] + "…"
+	}
+
+	return string(cut) + "…"
+}
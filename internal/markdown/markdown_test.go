@@ -0,0 +1,79 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains string
+		excludes string
+	}{
+		{name: "emphasis", source: "Use **channels**", contains: "<strong>channels</strong>"},
+		{name: "code block", source: "```go\nfmt.Println(1)\n```", contains: "<code class=\"language-go\">"},
+		{name: "table", source: "| a | b |\n|---|---|\n| 1 | 2 |", contains: "<table>"},
+		{name: "raw html", source: "<script>alert(1)</script>", excludes: "<script>"},
+		{name: "javascript link", source: "[click](javascript:alert(1))", excludes: "javascript:"},
+		{name: "event handler", source: `<img src="x" onerror="alert(1)">`, excludes: "onerror"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToHTML(tt.source)
+
+			if tt.contains != "" && !strings.Contains(got, tt.contains) {
+				t.Errorf("expected %q in %q", tt.contains, got)
+			}
+
+			if tt.excludes != "" && strings.Contains(got, tt.excludes) {
+				t.Errorf("expected no %q in %q", tt.excludes, got)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		snippet  string
+		expected string
+	}{
+		{name: "matches", snippet: "What is <mark>Go</mark>?", expected: "What is <mark>Go</mark>?"},
+		{name: "event handler", snippet: `<img src=x onerror="alert(1)"> <mark>Go</mark>`, expected: `&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>Go</mark>`},
+		{name: "script", snippet: "<script>alert(1)</script>", expected: "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{name: "entities", snippet: "a < b && <mark>c</mark>", expected: "a &lt; b &amp;&amp; <mark>c</mark>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.snippet); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		maxLen   int
+		expected string
+	}{
+		{name: "markup removed", source: "# Title\n\nUse **channels** & `select`", maxLen: 100, expected: "Title Use channels & select"},
+		{name: "cut at word", source: "one two three four", maxLen: 10, expected: "one two…"},
+		{name: "single long word", source: "abcdefghij", maxLen: 4, expected: "abcd…"},
+		{name: "multibyte", source: "привет мир", maxLen: 8, expected: "привет…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Excerpt(tt.source, tt.maxLen); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	m := New()
+
+	m.ObserveRequest(http.MethodGet, "/questions/{id}", http.StatusOK, 50*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/questions/{id}", http.StatusNotFound, 10*time.Millisecond)
+	m.QuestionCreated()
+	m.AnswerCreated()
+	m.AnswerCreated()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+
+	for _, line := range []string{
+		`qa_http_requests_total{method="GET",route="/questions/{id}",status="200"} 1`,
+		`qa_http_requests_total{method="GET",route="/questions/{id}",status="404"} 1`,
+		`qa_http_request_duration_seconds_count{method="GET",route="/questions/{id}"} 2`,
+		`qa_questions_created_total 1`,
+		`qa_answers_created_total 2`,
+	} {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("expected %q in the metrics", line)
+		}
+	}
+}
+
+func TestObserveRequest_BoundsMethods(t *testing.T) {
+	m := New()
+
+	for _, method := range []string{"FOO", "BAR", "get"} {
+		m.ObserveRequest(method, "/questions", http.StatusMethodNotAllowed, time.Millisecond)
+	}
+	m.ObserveRequest(http.MethodDelete, "/questions", http.StatusMethodNotAllowed, time.Millisecond)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	for _, line := range []string{
+		`qa_http_requests_total{method="OTHER",route="/questions",status="405"} 3`,
+		`qa_http_requests_total{method="DELETE",route="/questions",status="405"} 1`,
+		`qa_http_request_duration_seconds_count{method="OTHER",route="/questions"} 3`,
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected %q in the metrics", line)
+		}
+	}
+
+	for _, method := range []string{"FOO", "BAR", "get"} {
+		if strings.Contains(body, `method="`+method+`"`) {
+			t.Errorf("expected no series for method %q", method)
+		}
+	}
+}
@@ -1,11 +1,20 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Answer struct {
-	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	QuestionID int       `gorm:"not null;index" json:"question_id"`
-	UserID     string    `gorm:"type:varchar(255);not null" json:"user_id"`
-	Text       string    `gorm:"type:text;not null" json:"text"`
-	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID         int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	QuestionID int            `gorm:"not null;index" json:"question_id"`
+	UserID     string         `gorm:"type:varchar(255);not null" json:"user_id"`
+	Body       string         `gorm:"type:text;not null" json:"body"` // Markdown
+	Score      int            `gorm:"not null;default:0" json:"score"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at"` // Set when the answer itself was deleted, not its question
+	DeletedBy  *string        `gorm:"type:varchar(255)" json:"deleted_by,omitempty"`
+	IsAccepted bool           `gorm:"->" json:"is_accepted"`
 }
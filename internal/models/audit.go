@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEvent is a change made to an entity. Before and After are JSON snapshots of it,
+// nil when it didn't exist before the change or is gone after it.
+type AuditEvent struct {
+	ID         int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	Actor      *string         `gorm:"type:varchar(255)" json:"actor"` // Nil for anonymous requests
+	Action     string          `gorm:"type:varchar(50);not null" json:"action"`
+	EntityType string          `gorm:"type:varchar(20);not null" json:"entity_type"`
+	EntityID   string          `gorm:"type:varchar(255);not null" json:"entity_id"`
+	Before     json.RawMessage `gorm:"type:jsonb" json:"before"`
+	After      json.RawMessage `gorm:"type:jsonb" json:"after"`
+	RequestID  *string         `gorm:"type:varchar(128)" json:"request_id"`
+	ClientIP   *string         `gorm:"type:varchar(45)" json:"client_ip"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime" json:"created_at"`
+}
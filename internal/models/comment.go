@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Comment is attached to either a question or an answer, exactly one of the IDs is set.
+// Replies have ParentID set and are only allowed on top-level comments.
+type Comment struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	QuestionID *int      `gorm:"index" json:"question_id"`
+	AnswerID   *int      `gorm:"index" json:"answer_id"`
+	ParentID   *int      `gorm:"index" json:"parent_id"`
+	UserID     string    `gorm:"type:varchar(255);not null" json:"user_id"`
+	Body       string    `gorm:"type:text;not null" json:"body"` // Markdown
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -1,10 +1,23 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Question struct {
-	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	Text      string    `gorm:"type:text;not null" json:"text"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	Answers   []Answer  `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"answers,omitempty"`
+	ID               int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	AuthorID         *string        `gorm:"type:varchar(255);index" json:"author_id"`
+	Title            string         `gorm:"type:varchar(150);not null" json:"title"`
+	Body             string         `gorm:"type:text;not null" json:"body"` // Markdown
+	Score            int            `gorm:"not null;default:0" json:"score"`
+	AcceptedAnswerID *int           `gorm:"index" json:"accepted_answer_id"` // One of the question's own answers
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"deleted_at"` // Queries skip deleted questions unless Unscoped
+	DeletedBy        *string        `gorm:"type:varchar(255)" json:"deleted_by,omitempty"`
+	AnswersCount     int            `gorm:"->" json:"answers_count"` // Only filled by List and GetByID
+	Answers          []Answer       `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"answers,omitempty"`
+	Tags             []Tag          `gorm:"many2many:question_tags" json:"tags,omitempty"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// QuestionRevision is a previous version of a question.
+// CreatedAt is when the version was written, ReplacedAt is when it was edited.
+type QuestionRevision struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	QuestionID int       `gorm:"not null;index" json:"question_id"`
+	Title      string    `gorm:"type:varchar(150);not null" json:"title"`
+	Body       string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt  time.Time `gorm:"not null" json:"created_at"`
+	ReplacedAt time.Time `gorm:"autoCreateTime" json:"replaced_at"`
+}
+
+type AnswerRevision struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AnswerID   int       `gorm:"not null;index" json:"answer_id"`
+	Body       string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt  time.Time `gorm:"not null" json:"created_at"`
+	ReplacedAt time.Time `gorm:"autoCreateTime" json:"replaced_at"`
+}
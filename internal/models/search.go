@@ -0,0 +1,10 @@
+package models
+
+// SearchHit is a question or answer matching a full-text query.
+// AnswerID is nil when the question itself matched.
+type SearchHit struct {
+	QuestionID int     `json:"question_id"`
+	AnswerID   *int    `json:"answer_id"`
+	Rank       float64 `json:"rank"`
+	Snippet    string  `json:"snippet"`
+}
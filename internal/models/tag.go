@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Tag names are stored normalized: lowercase, words joined with "-".
+type Tag struct {
+	ID             int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name           string    `gorm:"type:varchar(35);not null;uniqueIndex" json:"name"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	QuestionsCount int       `gorm:"->" json:"questions_count"` // Only filled by ListTags
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// User ID is the subject of the user's auth token.
+type User struct {
+	ID          string    `gorm:"primaryKey;type:varchar(255)" json:"id"`
+	DisplayName string    `gorm:"type:varchar(100);not null" json:"display_name"`
+	Bio         string    `gorm:"type:text;not null" json:"bio"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription receives the events it lists. Secret signs the deliveries, it is never serialized.
+type WebhookSubscription struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL       string    `gorm:"type:text;not null" json:"url"`
+	Events    []string  `gorm:"type:jsonb;serializer:json;not null" json:"events"`
+	Secret    string    `gorm:"type:varchar(64);not null" json:"-"`
+	CreatedBy string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed" // Out of attempts
+)
+
+// WebhookDelivery is an event queued for a subscription. Subscription is only filled by ClaimDeliveries.
+type WebhookDelivery struct {
+	ID             int                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubscriptionID int                  `gorm:"not null;index" json:"subscription_id"`
+	Event          string               `gorm:"type:varchar(50);not null" json:"event"`
+	Payload        json.RawMessage      `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string               `gorm:"type:varchar(20);not null;default:pending" json:"status"`
+	Attempts       int                  `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time            `gorm:"not null" json:"next_attempt_at"`
+	LastStatusCode *int                 `json:"last_status_code"`
+	LastError      *string              `gorm:"type:text" json:"last_error"`
+	CreatedAt      time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	DeliveredAt    *time.Time           `json:"delivered_at"`
+	Subscription   *WebhookSubscription `gorm:"-" json:"-"`
+}
+
+// WebhookPayload is the body of a delivery. ID is the audit event, the same for every subscription,
+// so that receivers can tell a retried delivery from a new event.
+type WebhookPayload struct {
+	ID         int             `json:"id"`
+	Event      string          `json:"event"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Actor      *string         `json:"actor"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"` // The entity after the event
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 1, Burst: 3}
+	ctx := context.Background()
+
+	take := func(key string) Result {
+		t.Helper()
+
+		result, err := store.Take(ctx, key, limit)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return result
+	}
+
+	for i := 2; i >= 0; i-- {
+		result := take("ip:1")
+		if !result.Allowed || result.Remaining != i || result.Limit != 3 {
+			t.Fatalf("expected request to be allowed with %d remaining, got %+v", i, result)
+		}
+	}
+
+	result := take("ip:1")
+	if result.Allowed || result.Remaining != 0 {
+		t.Fatalf("expected the burst to be used up, got %+v", result)
+	}
+	if result.RetryAfter != time.Second || result.Reset != 3*time.Second {
+		t.Errorf("expected retry after 1s and reset after 3s, got %+v", result)
+	}
+
+	if !take("ip:2").Allowed {
+		t.Error("expected other keys to have their own bucket")
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	if result := take("ip:1"); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("expected a refilled token, got %+v", result)
+	}
+	if result := take("ip:1"); result.Allowed || result.RetryAfter != 500*time.Millisecond {
+		t.Errorf("expected the half token to be kept, got %+v", result)
+	}
+
+	now = now.Add(time.Hour)
+	if result := take("ip:1"); result.Remaining != 2 {
+		t.Errorf("expected the bucket to refill up to the burst, got %+v", result)
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 1, Burst: 10}
+	for _, key := range []string{"ip:1", "ip:2"} {
+		if _, err := store.Take(context.Background(), key, limit); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	now = now.Add(sweepInterval)
+	if _, err := store.Take(context.Background(), "ip:3", limit); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(store.buckets) != 1 {
+		t.Errorf("expected only the new bucket to be kept, got %d buckets", len(store.buckets))
+	}
+}
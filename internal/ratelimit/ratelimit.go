@@ -0,0 +1,61 @@
+// Package ratelimit implements token buckets: a client may send Burst requests at once,
+// and the bucket refills at Rate tokens per second.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+type Limit struct {
+	Rate  float64 // Tokens per second
+	Burst int
+}
+
+// Enabled is false for a zero limit, which lets everything through.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // Until the next token, zero when allowed
+	Reset      time.Duration // Until the bucket is full again
+}
+
+// Store keeps the buckets. Keys are opaque, a bucket is created full on the first Take.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Refill adds the tokens accumulated over elapsed and takes one if there is one.
+func Refill(tokens float64, elapsed time.Duration, limit Limit) (left float64, allowed bool) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+	if tokens < 1 {
+		return tokens, false
+	}
+
+	return tokens - 1, true
+}
+
+// NewResult describes a bucket with tokens left after a Take.
+func NewResult(limit Limit, tokens float64, allowed bool) Result {
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(tokens),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the keyset position of the last item on a page.
+// Value is only used by sorts on an integer column (e.g. answers_count, score).
+type Cursor struct {
+	Sort      string    `json:"s"`
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"t"`
+	Value     int       `json:"v,omitempty"`
+}
+
+// Encode returns an opaque token that can be handed to clients.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c) // Can't fail for a struct of plain fields
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import "errors"
+
+// Implementations wrap their own errors with these, so callers can use errors.Is
+// without knowing the storage behind the repository.
+var (
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is a duplicate of a unique key.
+	ErrConflict = errors.New("record already exists")
+	// ErrForeignKeyViolation means a referenced record doesn't exist, e.g. it was deleted concurrently.
+	ErrForeignKeyViolation = errors.New("referenced record does not exist")
+	ErrCheckViolation      = errors.New("record violates a check constraint")
+	// ErrNotOwner means the acting user didn't write the record and isn't allowed to change it.
+	ErrNotOwner = errors.New("record belongs to another user")
+)
+
+// Foreign keys that callers tell apart, see ViolatedConstraint.
+const (
+	ConstraintAnswerQuestion = "fk_question"
+	ConstraintAnswerUser     = "fk_answer_user"
+)
+
+// ForeignKeyError is an ErrForeignKeyViolation that keeps the name of the violated constraint.
+type ForeignKeyError struct {
+	Constraint string
+	Err        error
+}
+
+func (e *ForeignKeyError) Error() string {
+	return ErrForeignKeyViolation.Error() + " (" + e.Constraint + "): " + e.Err.Error()
+}
+
+func (e *ForeignKeyError) Is(target error) bool {
+	return target == ErrForeignKeyViolation
+}
+
+func (e *ForeignKeyError) Unwrap() error {
+	return e.Err
+}
+
+// ViolatedConstraint returns the foreign key behind err, or "" when err isn't a ForeignKeyError.
+func ViolatedConstraint(err error) string {
+	var fkErr *ForeignKeyError
+	if errors.As(err, &fkErr) {
+		return fkErr.Constraint
+	}
+
+	return ""
+}
@@ -1,31 +1,298 @@
 package postgres
 
-import "github.com/makson2134/go-qa-service/internal/models"
+import (
+	"context"
+	"strconv"
 
-func (db *DB) CreateAnswer(questionID int, userID, text string) (*models.Answer, error) {
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const answerColumns = "answers.*, EXISTS (SELECT 1 FROM questions WHERE questions.accepted_answer_id = answers.id) AS is_accepted"
+
+// Answers of a deleted question are in the trash with it, so reads of single answers skip them as well
+const answerOfLiveQuestion = "EXISTS (SELECT 1 FROM questions WHERE questions.id = answers.question_id AND questions.deleted_at IS NULL)"
+
+func (db *DB) CreateAnswer(ctx context.Context, questionID int, userID, body string) (*models.Answer, error) {
 	answer := &models.Answer{
 		QuestionID: questionID,
 		UserID:     userID,
-		Text:       text,
+		Body:       body,
 	}
 
-	if err := db.conn.Create(answer).Error; err != nil {
-		return nil, err
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(answer).Error; err != nil {
+			return err
+		}
+
+		return recordEvent(tx, audit.AnswerCreated, audit.EntityAnswer, strconv.Itoa(answer.ID), nil, answer)
+	})
+	if err != nil {
+		return nil, translateError(err)
 	}
 
 	return answer, nil
 }
 
-func (db *DB) GetAnswerByID(id int) (*models.Answer, error) {
+func (db *DB) GetAnswerByID(ctx context.Context, id int) (*models.Answer, error) {
+	var answer models.Answer
+
+	if err := db.conn.WithContext(ctx).Select(answerColumns).Where(answerOfLiveQuestion).First(&answer, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &answer, nil
+}
+
+// ListAnswers fetches one extra row to find out whether there is a next page.
+// The accepted answer is only on the first page, later pages leave it out.
+func (db *DB) ListAnswers(ctx context.Context, questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error) {
+	var question models.Question
+
+	if err := db.conn.WithContext(ctx).Select("id", "accepted_answer_id").First(&question, questionID).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	query := db.conn.WithContext(ctx).Select(answerColumns).Where("answers.question_id = ?", questionID)
+
+	c := opts.Cursor
+	if accepted := question.AcceptedAnswerID; accepted != nil {
+		query = query.Order("is_accepted DESC")
+
+		if c != nil {
+			query = query.Where("answers.id <> ?", *accepted)
+			// The previous page ended with the accepted answer, the others start after it
+			if c.ID == *accepted {
+				c = nil
+			}
+		}
+	}
+
+	switch opts.Sort {
+	case repository.AnswerSortScore:
+		if c != nil {
+			query = query.Where("(answers.score < ? OR (answers.score = ? AND (answers.created_at, answers.id) > (?, ?)))", c.Value, c.Value, c.CreatedAt, c.ID)
+		}
+		query = query.Order("answers.score DESC, answers.created_at, answers.id")
+	case repository.AnswerSortNewest:
+		if c != nil {
+			query = query.Where("(answers.created_at, answers.id) < (?, ?)", c.CreatedAt, c.ID)
+		}
+		query = query.Order("answers.created_at DESC, answers.id DESC")
+	default:
+		if c != nil {
+			query = query.Where("(answers.created_at, answers.id) > (?, ?)", c.CreatedAt, c.ID)
+		}
+		query = query.Order("answers.created_at, answers.id")
+	}
+
+	var answers []models.Answer
+
+	if err := query.Limit(opts.Limit + 1).Find(&answers).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	if len(answers) <= opts.Limit {
+		return answers, nil, nil
+	}
+
+	answers = answers[:opts.Limit]
+	last := answers[len(answers)-1]
+	next := &repository.Cursor{
+		Sort:      string(opts.Sort),
+		ID:        last.ID,
+		CreatedAt: last.CreatedAt,
+	}
+	if opts.Sort == repository.AnswerSortScore {
+		next.Value = last.Score
+	}
+
+	return answers, next, nil
+}
+
+// UpdateAnswer keeps the replaced body as a revision. Editing to the same body is a no-op.
+func (db *DB) UpdateAnswer(ctx context.Context, id int, userID, body string) (*models.Answer, error) {
 	var answer models.Answer
 
-	if err := db.conn.First(&answer, id).Error; err != nil {
-		return nil, err
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "answers"}}).
+			Select(answerColumns).Where(answerOfLiveQuestion).First(&answer, id).Error
+		if err != nil {
+			return err
+		}
+
+		if answer.UserID != userID {
+			return repository.ErrNotOwner
+		}
+
+		if answer.Body == body {
+			return nil
+		}
+
+		before := answer
+
+		revision := &models.AnswerRevision{
+			AnswerID:  answer.ID,
+			Body:      answer.Body,
+			CreatedAt: answer.UpdatedAt,
+		}
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&answer).Update("body", body).Error; err != nil {
+			return err
+		}
+
+		return recordEvent(tx, audit.AnswerUpdated, audit.EntityAnswer, strconv.Itoa(answer.ID), &before, &answer)
+	})
+	if err != nil {
+		return nil, translateError(err)
 	}
 
 	return &answer, nil
 }
 
-func (db *DB) DeleteAnswer(id int) error {
-	return db.conn.Delete(&models.Answer{}, id).Error
+func (db *DB) ListAnswerRevisions(ctx context.Context, answerID int) ([]models.AnswerRevision, error) {
+	var revisions []models.AnswerRevision
+
+	err := db.conn.WithContext(ctx).Where("answer_id = ?", answerID).
+		Order("replaced_at DESC, id DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return revisions, nil
+}
+
+// VoteAnswer returns the score of the answer after the vote.
+func (db *DB) VoteAnswer(ctx context.Context, answerID int, userID string, value int) (int, error) {
+	var answer models.Answer
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "answers"}}).
+			Select("id", "score").Where(answerOfLiveQuestion).First(&answer, answerID).Error
+		if err != nil {
+			return err
+		}
+
+		previous, err := castVote(tx, "answer_votes", "answer_id", answerID, userID, value)
+		if err != nil || previous == value {
+			return err
+		}
+
+		before := voteSnapshot{Score: answer.Score, Vote: previousVote(previous)}
+		answer.Score += value - previous
+
+		if err := tx.Model(&answer).UpdateColumn("score", answer.Score).Error; err != nil {
+			return err
+		}
+
+		after := voteSnapshot{Score: answer.Score, Vote: &value}
+
+		return recordEvent(tx, audit.AnswerVoted, audit.EntityAnswer, strconv.Itoa(answerID), before, after)
+	})
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return answer.Score, nil
+}
+
+// DeleteAnswer keeps the answer in the trash until it is purged. If it was accepted, the question
+// no longer has an accepted answer, as with the foreign key on a hard delete.
+func (db *DB) DeleteAnswer(ctx context.Context, id int, userID string, admin bool) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Answer
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "answers"}}).
+			Select(answerColumns).Where(answerOfLiveQuestion).First(&before, id).Error
+		if err != nil {
+			return err
+		}
+
+		if !admin && before.UserID != userID {
+			return repository.ErrNotOwner
+		}
+
+		err = tx.Model(&models.Answer{}).
+			Where("id = ?", id).
+			UpdateColumns(map[string]any{"deleted_at": gorm.Expr("CURRENT_TIMESTAMP"), "deleted_by": userID}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.Question{}).
+			Where("accepted_answer_id = ?", id).
+			UpdateColumn("accepted_answer_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		return recordAnswerChange(tx, audit.AnswerDeleted, &before)
+	})
+
+	return translateError(err)
+}
+
+func (db *DB) GetDeletedAnswer(ctx context.Context, id int) (*models.Answer, error) {
+	var answer models.Answer
+
+	err := db.conn.WithContext(ctx).Unscoped().Where("answers.deleted_at IS NOT NULL").Where(answerOfLiveQuestion).First(&answer, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &answer, nil
+}
+
+// RestoreAnswer returns repository.ErrNotFound unless the answer is in the trash, and while its question
+// is in the trash as well, the answer comes back when the question is restored. It doesn't accept the answer again.
+func (db *DB) RestoreAnswer(ctx context.Context, id int) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Answer
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "answers"}}).
+			Select(answerColumns).Where("answers.deleted_at IS NOT NULL").First(&before, id).Error
+		if err != nil {
+			return err
+		}
+
+		// Locked so that the question can't be deleted before the answer is restored
+		var questionIDs []int
+		err = tx.Model(&models.Question{}).
+			Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("id = ?", before.QuestionID).
+			Pluck("id", &questionIDs).Error
+		if err != nil {
+			return err
+		}
+
+		if len(questionIDs) == 0 {
+			return repository.ErrNotFound
+		}
+
+		err = tx.Unscoped().Model(&models.Answer{}).
+			Where("id = ?", id).
+			UpdateColumns(map[string]any{"deleted_at": nil, "deleted_by": nil}).Error
+		if err != nil {
+			return err
+		}
+
+		return recordAnswerChange(tx, audit.AnswerRestored, &before)
+	})
+
+	return translateError(err)
+}
+
+// recordAnswerChange records the answer as it is now in tx, after the change.
+func recordAnswerChange(tx *gorm.DB, action string, before *models.Answer) error {
+	var after models.Answer
+	if err := tx.Unscoped().Select(answerColumns).First(&after, before.ID).Error; err != nil {
+		return err
+	}
+
+	return recordEvent(tx, action, audit.EntityAnswer, strconv.Itoa(before.ID), before, &after)
 }
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// voteSnapshot is what a vote changes: the score of the entity and the vote of the actor, nil when they hadn't voted.
+type voteSnapshot struct {
+	Score int  `json:"score"`
+	Vote  *int `json:"vote"`
+}
+
+// recordEvent appends an audit event in tx and queues it for the webhooks subscribed to it,
+// the actor is taken from the context of tx. before and after are stored as JSON, nil as NULL.
+func recordEvent(tx *gorm.DB, action, entityType, entityID string, before, after any) error {
+	event := &models.AuditEvent{Action: action, EntityType: entityType, EntityID: entityID}
+
+	var err error
+	if event.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if event.After, err = snapshot(after); err != nil {
+		return err
+	}
+
+	if actor, ok := audit.ActorFromContext(tx.Statement.Context); ok {
+		event.Actor = nonEmpty(actor.UserID)
+		event.RequestID = nonEmpty(actor.RequestID)
+		event.ClientIP = nonEmpty(actor.ClientIP)
+	}
+
+	if err := tx.Create(event).Error; err != nil {
+		return err
+	}
+
+	return enqueueDeliveries(tx, event)
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(v)
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// ListAuditEvents returns the newest events first and fetches one extra row to find out whether there is a next page.
+func (db *DB) ListAuditEvents(ctx context.Context, filter repository.AuditFilter, limit int, cursor *repository.Cursor) ([]models.AuditEvent, *repository.Cursor, error) {
+	query := db.conn.WithContext(ctx).Model(&models.AuditEvent{})
+
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if cursor != nil {
+		query = query.Where("id < ?", cursor.ID)
+	}
+
+	var events []models.AuditEvent
+
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&events).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	if len(events) <= limit {
+		return events, nil, nil
+	}
+
+	events = events[:limit]
+	last := events[len(events)-1]
+
+	return events, &repository.Cursor{ID: last.ID, CreatedAt: last.CreatedAt}, nil
+}
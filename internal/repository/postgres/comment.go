@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (db *DB) CreateComment(ctx context.Context, target repository.CommentTarget, parentID *int, userID, body string) (*models.Comment, error) {
+	comment := &models.Comment{
+		ParentID: parentID,
+		UserID:   userID,
+		Body:     body,
+	}
+	if target.QuestionID != 0 {
+		comment.QuestionID = &target.QuestionID
+	} else {
+		comment.AnswerID = &target.AnswerID
+	}
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+
+		return recordEvent(tx, audit.CommentCreated, audit.EntityComment, strconv.Itoa(comment.ID), nil, comment)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return comment, nil
+}
+
+func (db *DB) GetCommentByID(ctx context.Context, id int) (*models.Comment, error) {
+	var comment models.Comment
+
+	if err := db.conn.WithContext(ctx).First(&comment, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &comment, nil
+}
+
+func (db *DB) ListComments(ctx context.Context, target repository.CommentTarget) ([]models.Comment, error) {
+	query := db.conn.WithContext(ctx).Order("created_at, id")
+	if target.QuestionID != 0 {
+		query = query.Where("question_id = ?", target.QuestionID)
+	} else {
+		query = query.Where("answer_id = ?", target.AnswerID)
+	}
+
+	var comments []models.Comment
+
+	if err := query.Find(&comments).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return comments, nil
+}
+
+func (db *DB) ListQuestionComments(ctx context.Context, questionID int) ([]models.Comment, error) {
+	var comments []models.Comment
+
+	err := db.conn.WithContext(ctx).Where("question_id = ? OR answer_id IN (SELECT id FROM answers WHERE question_id = ? AND deleted_at IS NULL)", questionID, questionID).
+		Order("created_at, id").
+		Find(&comments).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return comments, nil
+}
+
+// DeleteComment also deletes the replies to the comment, only the comment itself is recorded in the audit log.
+func (db *DB) DeleteComment(ctx context.Context, id int) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+
+		result := tx.Clauses(clause.Returning{}).Delete(&comment, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+
+		return recordEvent(tx, audit.CommentDeleted, audit.EntityComment, strconv.Itoa(id), &comment, nil)
+	})
+
+	return translateError(err)
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	checkViolation      = "23514"
+)
+
+// translateError turns GORM and Postgres errors into repository errors, keeping the cause for logs.
+// Unknown errors, including context cancellation, are returned as is.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrConflict) ||
+		errors.Is(err, repository.ErrForeignKeyViolation) || errors.Is(err, repository.ErrCheckViolation) ||
+		errors.Is(err, repository.ErrNotOwner) {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %w", repository.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case foreignKeyViolation:
+			return &repository.ForeignKeyError{Constraint: pgErr.ConstraintName, Err: err}
+		case uniqueViolation:
+			return fmt.Errorf("%w: %w", repository.ErrConflict, err)
+		case checkViolation:
+			return fmt.Errorf("%w: %w", repository.ErrCheckViolation, err)
+		}
+	}
+
+	return err
+}
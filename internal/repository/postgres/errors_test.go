@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "record not found", err: gorm.ErrRecordNotFound, expected: repository.ErrNotFound},
+		{name: "wrapped record not found", err: fmt.Errorf("query: %w", gorm.ErrRecordNotFound), expected: repository.ErrNotFound},
+		{name: "foreign key", err: &pgconn.PgError{Code: "23503", ConstraintName: "fk_answer_question"}, expected: repository.ErrForeignKeyViolation},
+		{name: "unique", err: &pgconn.PgError{Code: "23505"}, expected: repository.ErrConflict},
+		{name: "check", err: &pgconn.PgError{Code: "23514"}, expected: repository.ErrCheckViolation},
+		{name: "already translated", err: repository.ErrConflict, expected: repository.ErrConflict},
+		{name: "context", err: context.Canceled, expected: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err)
+
+			if !errors.Is(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+
+			if !errors.Is(got, tt.err) {
+				t.Errorf("expected the cause %v to be kept, got %v", tt.err, got)
+			}
+		})
+	}
+
+	if translateError(nil) != nil {
+		t.Error("expected nil for nil")
+	}
+
+	fkErr := translateError(&pgconn.PgError{Code: "23503", ConstraintName: "fk_answer_user"})
+	if constraint := repository.ViolatedConstraint(fkErr); constraint != repository.ConstraintAnswerUser {
+		t.Errorf("expected the constraint %q to be kept, got %q", repository.ConstraintAnswerUser, constraint)
+	}
+
+	var pgErr *pgconn.PgError
+	if err := translateError(&pgconn.PgError{Code: "40001"}); !errors.As(err, &pgErr) || errors.Is(err, repository.ErrConflict) {
+		t.Errorf("expected unknown Postgres errors to pass through, got %v", err)
+	}
+}
@@ -1,10 +1,13 @@
 package postgres
 
 import (
+	"context"
 	"database/sql" //For goose migrations
 	"fmt"
 	"time"
 
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -13,12 +16,17 @@ type DB struct {
 	conn *gorm.DB
 }
 
-func New(dsn string, maxOpenConns, maxIdleConns int, connMaxLifetime time.Duration) (*DB, error) {
+// New traces every query with tp, pass noop.NewTracerProvider() to turn it off.
+func New(dsn string, maxOpenConns, maxIdleConns int, connMaxLifetime time.Duration, tp trace.TracerProvider) (*DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := db.Use(newTracingPlugin(tp)); err != nil {
+		return nil, fmt.Errorf("failed to set up query tracing: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
@@ -35,6 +43,17 @@ func New(dsn string, maxOpenConns, maxIdleConns int, connMaxLifetime time.Durati
 	return &DB{conn: db}, nil
 }
 
+// WithTx gives fn a DB bound to the transaction, so every repository method called on it runs inside.
+func (db *DB) WithTx(ctx context.Context, fn func(tx repository.Repositories) error) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txDB := &DB{conn: tx}
+
+		return fn(repository.Repositories{Questions: txDB, Answers: txDB})
+	})
+
+	return translateError(err)
+}
+
 func (db *DB) Close() error {
 	sqlDB, err := db.conn.DB()
 
@@ -1,37 +1,375 @@
 package postgres
 
-import "github.com/makson2134/go-qa-service/internal/models"
+import (
+	"context"
+	"slices"
+	"strconv"
 
-func (db *DB) Create(text string) (*models.Question, error) {
-	question := &models.Question{Text: text}
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
-	if err := db.conn.Create(question).Error; err != nil {
-		return nil, err
+const answersCountExpr = "(SELECT COUNT(*) FROM answers WHERE answers.question_id = questions.id AND answers.deleted_at IS NULL)"
+
+func (db *DB) Create(ctx context.Context, authorID, title, body string, tags []string) (*models.Question, error) {
+	question := &models.Question{AuthorID: &authorID, Title: title, Body: body}
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(question).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if question.Tags, err = setQuestionTags(tx, question.ID, tags); err != nil {
+			return err
+		}
+
+		return recordEvent(tx, audit.QuestionCreated, audit.EntityQuestion, strconv.Itoa(question.ID), nil, question)
+	})
+	if err != nil {
+		return nil, translateError(err)
 	}
 
 	return question, nil
 }
 
-func (db *DB) GetByID(id int) (*models.Question, error) {
+// GetByID counts the answers without loading them, there may be thousands: see ListAnswers.
+func (db *DB) GetByID(ctx context.Context, id int) (*models.Question, error) {
 	var question models.Question
 
-	if err := db.conn.Preload("Answers").First(&question, id).Error; err != nil {
-		return nil, err
+	err := db.conn.WithContext(ctx).Select("questions.*, "+answersCountExpr+" AS answers_count").
+		Preload("Tags", orderTags).First(&question, id).Error
+	if err != nil {
+		return nil, translateError(err)
 	}
 
 	return &question, nil
 }
 
-func (db *DB) List() ([]models.Question, error) {
+// Exists takes a SHARE lock: moving the question to the trash is an update, so KEY SHARE wouldn't stop it.
+// Votes and edits of the question wait for the transaction too. Outside a transaction the lock is released right away.
+func (db *DB) Exists(ctx context.Context, id int) (bool, error) {
+	var ids []int
+
+	err := db.conn.WithContext(ctx).Model(&models.Question{}).
+		Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("id = ?", id).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return false, translateError(err)
+	}
+
+	return len(ids) > 0, nil
+}
+
+// List fetches one extra row to find out whether there is a next page.
+func (db *DB) List(ctx context.Context, opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error) {
+	query := db.conn.WithContext(ctx).Model(&models.Question{}).
+		Select("questions.*, "+answersCountExpr+" AS answers_count").
+		Preload("Tags", orderTags)
+
+	if opts.AuthorID != "" {
+		query = query.Where("questions.author_id = ?", opts.AuthorID)
+	}
+	if len(opts.Tags) > 0 {
+		tagged := db.conn.WithContext(ctx).Table("question_tags").
+			Select("question_tags.question_id").
+			Joins("JOIN tags ON tags.id = question_tags.tag_id").
+			Where("tags.name IN ?", opts.Tags)
+		if !opts.MatchAnyTag {
+			tagged = tagged.Group("question_tags.question_id").Having("COUNT(*) = ?", len(opts.Tags))
+		}
+		query = query.Where("questions.id IN (?)", tagged)
+	}
+	if opts.CreatedAfter != nil {
+		query = query.Where("questions.created_at > ?", *opts.CreatedAfter)
+	}
+	if opts.CreatedBefore != nil {
+		query = query.Where("questions.created_at < ?", *opts.CreatedBefore)
+	}
+
+	c := opts.Cursor
+	switch opts.Sort {
+	case repository.SortCreatedDesc:
+		if c != nil {
+			query = query.Where("(questions.created_at, questions.id) < (?, ?)", c.CreatedAt, c.ID)
+		}
+		query = query.Order("questions.created_at DESC, questions.id DESC")
+	case repository.SortAnswersCount:
+		if c != nil {
+			query = query.Where("("+answersCountExpr+", questions.id) < (?, ?)", c.Value, c.ID)
+		}
+		query = query.Order("answers_count DESC, questions.id DESC")
+	case repository.SortScore:
+		if c != nil {
+			query = query.Where("(questions.score, questions.id) < (?, ?)", c.Value, c.ID)
+		}
+		query = query.Order("questions.score DESC, questions.id DESC")
+	default:
+		if c != nil {
+			query = query.Where("(questions.created_at, questions.id) > (?, ?)", c.CreatedAt, c.ID)
+		}
+		query = query.Order("questions.created_at, questions.id")
+	}
+
 	var questions []models.Question
 
-	if err := db.conn.Find(&questions).Error; err != nil {
-		return nil, err
+	if err := query.Limit(opts.Limit + 1).Find(&questions).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	if len(questions) <= opts.Limit {
+		return questions, nil, nil
+	}
+
+	questions = questions[:opts.Limit]
+	last := questions[len(questions)-1]
+	next := &repository.Cursor{
+		Sort:      string(opts.Sort),
+		ID:        last.ID,
+		CreatedAt: last.CreatedAt,
+	}
+	switch opts.Sort {
+	case repository.SortAnswersCount:
+		next.Value = last.AnswersCount
+	case repository.SortScore:
+		next.Value = last.Score
+	}
+
+	return questions, next, nil
+}
+
+// Update keeps the replaced title and body as a revision. Setting the same ones doesn't create it.
+func (db *DB) Update(ctx context.Context, id int, userID string, changes repository.QuestionChanges) (*models.Question, error) {
+	var question models.Question
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, id).Error; err != nil {
+			return err
+		}
+
+		if !ownedBy(question.AuthorID, userID) {
+			return repository.ErrNotOwner
+		}
+
+		before := question
+		var err error
+		if before.Tags, err = questionTags(tx, question.ID); err != nil {
+			return err
+		}
+
+		updates := map[string]any{}
+		if changes.Title != nil && *changes.Title != question.Title {
+			updates["title"] = *changes.Title
+		}
+		if changes.Body != nil && *changes.Body != question.Body {
+			updates["body"] = *changes.Body
+		}
+
+		if len(updates) > 0 {
+			revision := &models.QuestionRevision{
+				QuestionID: question.ID,
+				Title:      question.Title,
+				Body:       question.Body,
+				CreatedAt:  question.UpdatedAt,
+			}
+			if err := tx.Create(revision).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&question).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		question.Tags = before.Tags
+		if changes.Tags != nil {
+			if question.Tags, err = setQuestionTags(tx, question.ID, *changes.Tags); err != nil {
+				return err
+			}
+		}
+
+		// Saving the question as it is isn't a change
+		if len(updates) == 0 && slices.EqualFunc(before.Tags, question.Tags, sameTag) {
+			return nil
+		}
+
+		return recordEvent(tx, audit.QuestionUpdated, audit.EntityQuestion, strconv.Itoa(question.ID), &before, &question)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &question, nil
+}
+
+func (db *DB) ListRevisions(ctx context.Context, questionID int) ([]models.QuestionRevision, error) {
+	var revisions []models.QuestionRevision
+
+	err := db.conn.WithContext(ctx).Where("question_id = ?", questionID).
+		Order("replaced_at DESC, id DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return revisions, nil
+}
+
+// Vote returns the score of the question after the vote.
+func (db *DB) Vote(ctx context.Context, questionID int, userID string, value int) (int, error) {
+	var question models.Question
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "score").First(&question, questionID).Error; err != nil {
+			return err
+		}
+
+		previous, err := castVote(tx, "question_votes", "question_id", questionID, userID, value)
+		if err != nil || previous == value {
+			return err
+		}
+
+		before := voteSnapshot{Score: question.Score, Vote: previousVote(previous)}
+		question.Score += value - previous
+
+		if err := tx.Model(&question).UpdateColumn("score", question.Score).Error; err != nil {
+			return err
+		}
+
+		after := voteSnapshot{Score: question.Score, Vote: &value}
+
+		return recordEvent(tx, audit.QuestionVoted, audit.EntityQuestion, strconv.Itoa(questionID), before, after)
+	})
+	if err != nil {
+		return 0, translateError(err)
 	}
 
-	return questions, nil
+	return question.Score, nil
+}
+
+// AcceptAnswer returns repository.ErrNotFound unless the answer belongs to the question.
+func (db *DB) AcceptAnswer(ctx context.Context, questionID, answerID int) error {
+	return db.setAcceptedAnswer(ctx, questionID, &answerID, audit.QuestionAccepted)
+}
+
+func (db *DB) UnacceptAnswer(ctx context.Context, questionID int) error {
+	return db.setAcceptedAnswer(ctx, questionID, nil, audit.QuestionUnaccepted)
+}
+
+// setAcceptedAnswer holds the lock on the question, so an answer deleted meanwhile is unaccepted once it is released.
+func (db *DB) setAcceptedAnswer(ctx context.Context, questionID int, answerID *int, action string) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var question models.Question
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, questionID).Error; err != nil {
+			return err
+		}
+
+		if answerID != nil {
+			var ids []int
+			err := tx.Model(&models.Answer{}).Where("id = ? AND question_id = ?", *answerID, questionID).Pluck("id", &ids).Error
+			if err != nil {
+				return err
+			}
+
+			if len(ids) == 0 {
+				return repository.ErrNotFound
+			}
+		}
+
+		before := question
+		if err := tx.Model(&question).UpdateColumn("accepted_answer_id", answerID).Error; err != nil {
+			return err
+		}
+		question.AcceptedAnswerID = answerID
+
+		return recordEvent(tx, action, audit.EntityQuestion, strconv.Itoa(questionID), &before, &question)
+	})
+
+	return translateError(err)
+}
+
+// Delete keeps the question in the trash until it is purged, the answers are left as they are
+// and come back with it on Restore.
+func (db *DB) Delete(ctx context.Context, id int, userID string, admin bool) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Question
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, id).Error; err != nil {
+			return err
+		}
+
+		if !admin && !ownedBy(before.AuthorID, userID) {
+			return repository.ErrNotOwner
+		}
+
+		err := tx.Model(&models.Question{}).
+			Where("id = ?", id).
+			UpdateColumns(map[string]any{"deleted_at": gorm.Expr("CURRENT_TIMESTAMP"), "deleted_by": userID}).Error
+		if err != nil {
+			return err
+		}
+
+		return recordQuestionChange(tx, audit.QuestionDeleted, &before)
+	})
+
+	return translateError(err)
+}
+
+func (db *DB) GetDeleted(ctx context.Context, id int) (*models.Question, error) {
+	var question models.Question
+
+	if err := db.conn.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&question, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &question, nil
+}
+
+// Restore returns repository.ErrNotFound unless the question is in the trash.
+func (db *DB) Restore(ctx context.Context, id int) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Question
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").First(&before, id).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&models.Question{}).
+			Where("id = ?", id).
+			UpdateColumns(map[string]any{"deleted_at": nil, "deleted_by": nil}).Error
+		if err != nil {
+			return err
+		}
+
+		return recordQuestionChange(tx, audit.QuestionRestored, &before)
+	})
+
+	return translateError(err)
+}
+
+// recordQuestionChange records the question as it is now in tx, after the change.
+func recordQuestionChange(tx *gorm.DB, action string, before *models.Question) error {
+	var after models.Question
+	if err := tx.Unscoped().First(&after, before.ID).Error; err != nil {
+		return err
+	}
+
+	return recordEvent(tx, action, audit.EntityQuestion, strconv.Itoa(before.ID), before, &after)
+}
+
+func sameTag(a, b models.Tag) bool {
+	return a.ID == b.ID
+}
+
+func orderTags(tx *gorm.DB) *gorm.DB {
+	return tx.Order("tags.name")
 }
 
-func (db *DB) Delete(id int) error {
-	return db.conn.Delete(&models.Question{}, id).Error
+// ownedBy is false for questions without an author, e.g. from before authors were recorded.
+func ownedBy(authorID *string, userID string) bool {
+	return authorID != nil && *authorID == userID
 }
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/ratelimit"
+)
+
+const rateLimitSweepInterval = time.Minute
+
+// The bucket is refilled and taken from in one statement, the row lock orders concurrent requests.
+// Same math as ratelimit.Refill.
+const takeTokenQuery = `
+INSERT INTO rate_limits (key, tokens, allowed, updated_at, full_at)
+VALUES (@key, CAST(@burst AS float8) - 1, true, now(), now() + make_interval(secs => 1 / CAST(@rate AS float8)))
+ON CONFLICT (key) DO UPDATE SET (tokens, allowed, updated_at, full_at) = (
+    SELECT taken.tokens, taken.allowed, now(),
+           now() + make_interval(secs => (CAST(@burst AS float8) - taken.tokens) / CAST(@rate AS float8))
+    FROM (
+        SELECT CASE WHEN refilled >= 1 THEN refilled - 1 ELSE refilled END AS tokens, refilled >= 1 AS allowed
+        FROM (
+            SELECT LEAST(CAST(@burst AS float8),
+                         rate_limits.tokens + EXTRACT(EPOCH FROM now() - rate_limits.updated_at)::float8 * CAST(@rate AS float8)) AS refilled
+        ) AS bucket
+    ) AS taken
+)
+RETURNING tokens, allowed`
+
+// RateLimitStore keeps token buckets in PostgreSQL, so that all replicas share them.
+type RateLimitStore struct {
+	db     *DB
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewRateLimitStore(db *DB, logger *slog.Logger) *RateLimitStore {
+	return &RateLimitStore{db: db, logger: logger}
+}
+
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	var bucket struct {
+		Tokens  float64
+		Allowed bool
+	}
+
+	err := s.db.conn.WithContext(ctx).Raw(takeTokenQuery,
+		sql.Named("key", key), sql.Named("rate", limit.Rate), sql.Named("burst", limit.Burst),
+	).Scan(&bucket).Error
+	if err != nil {
+		return ratelimit.Result{}, translateError(err)
+	}
+
+	s.sweep(ctx)
+
+	return ratelimit.NewResult(limit, bucket.Tokens, bucket.Allowed), nil
+}
+
+// sweep deletes full buckets once in a while. A failed sweep is logged and retried later.
+func (s *RateLimitStore) sweep(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastSweep) < rateLimitSweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = time.Now()
+	s.mu.Unlock()
+
+	err := s.db.conn.WithContext(ctx).Exec("DELETE FROM rate_limits WHERE full_at <= now()").Error
+	// Not worth logging when the request was cancelled meanwhile
+	if err != nil && ctx.Err() == nil {
+		s.logger.ErrorContext(ctx, "failed to delete expired rate limit buckets", "error", err)
+	}
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+)
+
+// Headlines are only built for the rows that made it into the page,
+// ts_headline is much more expensive than the GIN lookup itself.
+const searchQuery = `
+WITH query AS (SELECT websearch_to_tsquery('english', @query) AS q),
+hits AS (
+    SELECT questions.id AS question_id, NULL::integer AS answer_id, questions.title || E'\n' || questions.body AS text,
+           ts_rank(questions.search_vector, query.q) AS rank
+    FROM questions, query
+    WHERE questions.search_vector @@ query.q AND questions.deleted_at IS NULL
+    UNION ALL
+    SELECT answers.question_id, answers.id, answers.body,
+           ts_rank(answers.search_vector, query.q)
+    FROM answers JOIN questions ON questions.id = answers.question_id, query
+    WHERE answers.search_vector @@ query.q AND answers.deleted_at IS NULL AND questions.deleted_at IS NULL
+    ORDER BY rank DESC, question_id, answer_id NULLS FIRST
+    LIMIT @limit
+)
+SELECT hits.question_id, hits.answer_id, hits.rank,
+       ts_headline('english', hits.text, query.q,
+                   'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2') AS snippet
+FROM hits, query
+ORDER BY hits.rank DESC, hits.question_id, hits.answer_id NULLS FIRST`
+
+func (db *DB) Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	var hits []models.SearchHit
+
+	err := db.conn.WithContext(ctx).Raw(searchQuery, sql.Named("query", query), sql.Named("limit", limit)).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListTags returns tags in use, the most popular first.
+func (db *DB) ListTags(ctx context.Context, limit int) ([]models.Tag, error) {
+	var tags []models.Tag
+
+	err := db.conn.WithContext(ctx).Model(&models.Tag{}).
+		Select("tags.*, COUNT(question_tags.question_id) AS questions_count").
+		Joins("JOIN question_tags ON question_tags.tag_id = tags.id").
+		Joins("JOIN questions ON questions.id = question_tags.question_id AND questions.deleted_at IS NULL").
+		Group("tags.id").
+		Order("questions_count DESC, tags.name").
+		Limit(limit).
+		Find(&tags).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return tags, nil
+}
+
+// setQuestionTags replaces the tags of the question, creating the tags that don't exist yet.
+func setQuestionTags(tx *gorm.DB, questionID int, names []string) ([]models.Tag, error) {
+	if err := tx.Exec("DELETE FROM question_tags WHERE question_id = ?", questionID).Error; err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		tags[i] = models.Tag{Name: name}
+	}
+
+	err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// IDs of the tags that already existed are not returned by the insert
+	tags = nil
+	if err := tx.Where("name IN ?", names).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	links := make([]map[string]any, len(tags))
+	for i, tag := range tags {
+		links[i] = map[string]any{"question_id": questionID, "tag_id": tag.ID}
+	}
+
+	if err := tx.Table("question_tags").Create(links).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func questionTags(tx *gorm.DB, questionID int) ([]models.Tag, error) {
+	var tags []models.Tag
+
+	err := tx.Joins("JOIN question_tags ON question_tags.tag_id = tags.id").
+		Where("question_tags.question_id = ?", questionID).
+		Order("tags.name").
+		Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	tracerName   = "github.com/makson2134/go-qa-service/internal/repository/postgres"
+	parentCtxKey = "tracing:parent_ctx"
+)
+
+// tracingPlugin wraps every GORM query in a client span, a child of the span in the query's context.
+type tracingPlugin struct {
+	tracer trace.Tracer
+}
+
+func newTracingPlugin(tp trace.TracerProvider) *tracingPlugin {
+	return &tracingPlugin{tracer: tp.Tracer(tracerName)}
+}
+
+func (p *tracingPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *tracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	// "*" puts the span around all other callbacks, so preloads and hooks become its children
+	return errors.Join(
+		cb.Create().Before("*").Register("tracing:before_create", p.before("INSERT")),
+		cb.Create().After("*").Register("tracing:after_create", p.after("INSERT")),
+		cb.Query().Before("*").Register("tracing:before_query", p.before("SELECT")),
+		cb.Query().After("*").Register("tracing:after_query", p.after("SELECT")),
+		cb.Update().Before("*").Register("tracing:before_update", p.before("UPDATE")),
+		cb.Update().After("*").Register("tracing:after_update", p.after("UPDATE")),
+		cb.Delete().Before("*").Register("tracing:before_delete", p.before("DELETE")),
+		cb.Delete().After("*").Register("tracing:after_delete", p.after("DELETE")),
+		cb.Row().Before("*").Register("tracing:before_row", p.before("SELECT")),
+		cb.Row().After("*").Register("tracing:after_row", p.after("SELECT")),
+		cb.Raw().Before("*").Register("tracing:before_raw", p.before("EXEC")),
+		cb.Raw().After("*").Register("tracing:after_raw", p.after("EXEC")),
+	)
+}
+
+func (p *tracingPlugin) before(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+
+		ctx, _ := p.tracer.Start(parent, operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBOperationName(operation)),
+		)
+
+		tx.Statement.Context = ctx
+		tx.InstanceSet(parentCtxKey, parent)
+	}
+}
+
+func (p *tracingPlugin) after(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		parent, ok := tx.InstanceGet(parentCtxKey)
+		if !ok {
+			return
+		}
+
+		span := trace.SpanFromContext(tx.Statement.Context)
+		tx.Statement.Context = parent.(context.Context)
+
+		// The table is only known once the statement is built
+		if table := tx.Statement.Table; table != "" {
+			span.SetName(operation + " " + table)
+			span.SetAttributes(semconv.DBCollectionName(table))
+		}
+		span.SetAttributes(
+			semconv.DBQueryText(tx.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+		)
+
+		// Missing rows are reported to the caller as not found, the query itself worked
+		if err := tx.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestTracingPlugin(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	// Dry run builds the statements without a server
+	conn, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := conn.Use(newTracingPlugin(tp)); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "GET /questions/{id}")
+	conn.WithContext(ctx).First(&models.Question{}, 1)
+	conn.WithContext(ctx).Where("id = ?", 1).Delete(&models.Answer{})
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	for i, expected := range []string{"SELECT questions", "DELETE answers"} {
+		span := spans[i]
+
+		if span.Name != expected {
+			t.Errorf("expected span %q, got %q", expected, span.Name)
+		}
+		if span.SpanKind != trace.SpanKindClient {
+			t.Errorf("expected client span, got %v", span.SpanKind)
+		}
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("expected %q to be a child of the request span", span.Name)
+		}
+		if query := attributeValue(span.Attributes, "db.query.text"); query == "" {
+			t.Errorf("expected %q to have the query text", span.Name)
+		}
+	}
+}
+
+func attributeValue(attrs []attribute.KeyValue, key string) string {
+	for _, attr := range attrs {
+		if string(attr.Key) == key {
+			return attr.Value.Emit()
+		}
+	}
+
+	return ""
+}
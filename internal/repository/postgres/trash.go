@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+// ListDeletedQuestions fetches one extra row to find out whether there is a next page.
+// The cursor keeps the deletion time in CreatedAt.
+func (db *DB) ListDeletedQuestions(ctx context.Context, limit int, cursor *repository.Cursor) ([]models.Question, *repository.Cursor, error) {
+	query := db.conn.WithContext(ctx).Unscoped().Where("questions.deleted_at IS NOT NULL")
+
+	if cursor != nil {
+		query = query.Where("(questions.deleted_at, questions.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var questions []models.Question
+
+	if err := query.Order("questions.deleted_at DESC, questions.id DESC").Limit(limit + 1).Find(&questions).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	if len(questions) <= limit {
+		return questions, nil, nil
+	}
+
+	questions = questions[:limit]
+	last := questions[len(questions)-1]
+
+	return questions, &repository.Cursor{ID: last.ID, CreatedAt: last.DeletedAt.Time}, nil
+}
+
+// ListDeletedAnswers leaves out the answers of deleted questions, they are in the trash with their question.
+func (db *DB) ListDeletedAnswers(ctx context.Context, limit int, cursor *repository.Cursor) ([]models.Answer, *repository.Cursor, error) {
+	query := db.conn.WithContext(ctx).Unscoped().Where("answers.deleted_at IS NOT NULL").Where(answerOfLiveQuestion)
+
+	if cursor != nil {
+		query = query.Where("(answers.deleted_at, answers.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var answers []models.Answer
+
+	if err := query.Order("answers.deleted_at DESC, answers.id DESC").Limit(limit + 1).Find(&answers).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	if len(answers) <= limit {
+		return answers, nil, nil
+	}
+
+	answers = answers[:limit]
+	last := answers[len(answers)-1]
+
+	return answers, &repository.Cursor{ID: last.ID, CreatedAt: last.DeletedAt.Time}, nil
+}
+
+// PurgeDeleted hard deletes answers first, the foreign keys then remove the answers, comments,
+// votes and revisions of the purged questions.
+func (db *DB) PurgeDeleted(ctx context.Context, before time.Time) (int64, int64, error) {
+	answers := db.conn.WithContext(ctx).Unscoped().Where("deleted_at < ?", before).Delete(&models.Answer{})
+	if answers.Error != nil {
+		return 0, 0, translateError(answers.Error)
+	}
+
+	questions := db.conn.WithContext(ctx).Unscoped().Where("deleted_at < ?", before).Delete(&models.Question{})
+	if questions.Error != nil {
+		return 0, answers.RowsAffected, translateError(questions.Error)
+	}
+
+	return questions.RowsAffected, answers.RowsAffected, nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateUser returns repository.ErrConflict when the user is already registered.
+func (db *DB) CreateUser(ctx context.Context, id, displayName, bio string) (*models.User, error) {
+	user := &models.User{
+		ID:          id,
+		DisplayName: displayName,
+		Bio:         bio,
+	}
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(user)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return repository.ErrConflict
+		}
+
+		return recordEvent(tx, audit.UserCreated, audit.EntityUser, user.ID, nil, user)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return user, nil
+}
+
+func (db *DB) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+
+	if err := db.conn.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &user, nil
+}
+
+// ListAnswersByUser returns the newest answers first.
+func (db *DB) ListAnswersByUser(ctx context.Context, userID string, limit int, cursor *repository.Cursor) ([]models.Answer, *repository.Cursor, error) {
+	query := db.conn.WithContext(ctx).Select(answerColumns).Where("answers.user_id = ?", userID).Where(answerOfLiveQuestion)
+
+	if cursor != nil {
+		query = query.Where("(answers.created_at, answers.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var answers []models.Answer
+
+	if err := query.Order("answers.created_at DESC, answers.id DESC").Limit(limit + 1).Find(&answers).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	if len(answers) <= limit {
+		return answers, nil, nil
+	}
+
+	answers = answers[:limit]
+	last := answers[len(answers)-1]
+
+	return answers, &repository.Cursor{ID: last.ID, CreatedAt: last.CreatedAt}, nil
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import "gorm.io/gorm"
+
+// castVote stores the vote of a user and returns their previous one, 0 when they hadn't voted.
+// The caller has to hold a lock on the voted row, so that votes of the same user are serialized.
+func castVote(tx *gorm.DB, table, targetColumn string, targetID int, userID string, value int) (int, error) {
+	voteOf := func() *gorm.DB {
+		return tx.Table(table).Where(targetColumn+" = ? AND user_id = ?", targetID, userID)
+	}
+
+	var previous []int
+	if err := voteOf().Pluck("value", &previous).Error; err != nil {
+		return 0, err
+	}
+
+	if len(previous) == 0 {
+		vote := map[string]any{targetColumn: targetID, "user_id": userID, "value": value}
+
+		return 0, tx.Table(table).Create(vote).Error
+	}
+
+	if previous[0] == value {
+		return value, nil
+	}
+
+	return previous[0], voteOf().Update("value", value).Error
+}
+
+// previousVote is nil when the user hadn't voted.
+func previousVote(previous int) *int {
+	if previous == 0 {
+		return nil
+	}
+
+	return &previous
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (db *DB) CreateWebhook(ctx context.Context, url string, events []string, secret, createdBy string) (*models.WebhookSubscription, error) {
+	subscription := &models.WebhookSubscription{URL: url, Events: events, Secret: secret, CreatedBy: createdBy}
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(subscription).Error; err != nil {
+			return err
+		}
+
+		return recordEvent(tx, audit.WebhookCreated, audit.EntityWebhook, strconv.Itoa(subscription.ID), nil, subscription)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return subscription, nil
+}
+
+func (db *DB) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+
+	if err := db.conn.WithContext(ctx).Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return subscriptions, nil
+}
+
+func (db *DB) DeleteWebhook(ctx context.Context, id int) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var subscription models.WebhookSubscription
+
+		result := tx.Clauses(clause.Returning{}).Delete(&subscription, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+
+		return recordEvent(tx, audit.WebhookDeleted, audit.EntityWebhook, strconv.Itoa(id), &subscription, nil)
+	})
+
+	return translateError(err)
+}
+
+// ListWebhookDeliveries fetches one extra row to find out whether there is a next page.
+func (db *DB) ListWebhookDeliveries(ctx context.Context, subscriptionID, limit int, cursor *repository.Cursor) ([]models.WebhookDelivery, *repository.Cursor, error) {
+	var ids []int
+	if err := db.conn.WithContext(ctx).Model(&models.WebhookSubscription{}).Where("id = ?", subscriptionID).Pluck("id", &ids).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil, repository.ErrNotFound
+	}
+
+	query := db.conn.WithContext(ctx).Where("subscription_id = ?", subscriptionID)
+	if cursor != nil {
+		query = query.Where("id < ?", cursor.ID)
+	}
+
+	var deliveries []models.WebhookDelivery
+
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&deliveries).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	if len(deliveries) <= limit {
+		return deliveries, nil, nil
+	}
+
+	deliveries = deliveries[:limit]
+	last := deliveries[len(deliveries)-1]
+
+	return deliveries, &repository.Cursor{ID: last.ID, CreatedAt: last.CreatedAt}, nil
+}
+
+// ClaimDeliveries skips the deliveries claimed by other replicas instead of waiting for them.
+func (db *DB) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= CURRENT_TIMESTAMP", models.DeliveryPending).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]int, len(deliveries))
+		subscriptionIDs := make([]int, 0, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+			subscriptionIDs = append(subscriptionIDs, delivery.SubscriptionID)
+		}
+
+		err = tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", gorm.Expr("CURRENT_TIMESTAMP + make_interval(secs => ?)", lease.Seconds())).Error
+		if err != nil {
+			return err
+		}
+
+		var subscriptions []models.WebhookSubscription
+		if err := tx.Where("id IN ?", subscriptionIDs).Find(&subscriptions).Error; err != nil {
+			return err
+		}
+
+		byID := make(map[int]*models.WebhookSubscription, len(subscriptions))
+		for i := range subscriptions {
+			byID[subscriptions[i].ID] = &subscriptions[i]
+		}
+		for i := range deliveries {
+			deliveries[i].Subscription = byID[deliveries[i].SubscriptionID]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return deliveries, nil
+}
+
+// FinishDelivery returns repository.ErrNotFound when the subscription was deleted meanwhile.
+func (db *DB) FinishDelivery(ctx context.Context, id int, result repository.DeliveryResult) error {
+	updates := map[string]any{
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_status_code": result.StatusCode,
+		"last_error":       nil,
+	}
+	switch {
+	case result.Delivered:
+		updates["status"] = models.DeliveryDelivered
+		updates["delivered_at"] = gorm.Expr("CURRENT_TIMESTAMP")
+	case result.NextAttemptAt != nil:
+		updates["next_attempt_at"] = *result.NextAttemptAt
+	default:
+		updates["status"] = models.DeliveryFailed
+	}
+	if result.Error != "" {
+		updates["last_error"] = result.Error
+	}
+
+	update := db.conn.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("id = ?", id).UpdateColumns(updates)
+	if update.Error != nil {
+		return translateError(update.Error)
+	}
+
+	if update.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// enqueueDeliveries queues the event for every subscription to it, in the transaction of the change.
+func enqueueDeliveries(tx *gorm.DB, event *models.AuditEvent) error {
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:         event.ID,
+		Event:      event.Action,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Actor:      event.Actor,
+		CreatedAt:  event.CreatedAt,
+		Data:       event.After,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`INSERT INTO webhook_deliveries (subscription_id, event, payload, next_attempt_at)
+		SELECT id, ?, ?, CURRENT_TIMESTAMP FROM webhook_subscriptions WHERE events @> jsonb_build_array(?::text)`,
+		event.Action, string(payload), event.Action).Error
+}
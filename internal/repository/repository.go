@@ -1,16 +1,177 @@
 package repository
 
-import "github.com/makson2134/go-qa-service/internal/models"
+import (
+	"context"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+)
+
+type QuestionSort string
+
+const (
+	SortCreatedAsc   QuestionSort = "created_at"
+	SortCreatedDesc  QuestionSort = "-created_at"
+	SortAnswersCount QuestionSort = "answers_count"
+	SortScore        QuestionSort = "score"
+)
+
+// ListQuestionsOptions describes a single page of questions.
+// Cursor is the position returned with the previous page, nil for the first one.
+type ListQuestionsOptions struct {
+	AuthorID      string   // Any author when empty
+	Tags          []string // Normalized tag names
+	MatchAnyTag   bool     // By default a question needs all of Tags
+	Limit         int
+	Sort          QuestionSort
+	Cursor        *Cursor
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+type AnswerSort string
+
+const (
+	AnswerSortScore  AnswerSort = "score"
+	AnswerSortNewest AnswerSort = "newest"
+	AnswerSortOldest AnswerSort = "oldest"
+)
+
+// ListAnswersOptions describes a single page of the answers of a question.
+type ListAnswersOptions struct {
+	Limit  int
+	Sort   AnswerSort
+	Cursor *Cursor
+}
+
+// QuestionChanges is a partial update, nil fields are left unchanged.
+type QuestionChanges struct {
+	Title *string
+	Body  *string
+	Tags  *[]string
+}
+
+// CommentTarget is the question or the answer comments are attached to, only one of the IDs is set.
+type CommentTarget struct {
+	QuestionID int
+	AnswerID   int
+}
 
 type QuestionRepository interface {
-	Create(text string) (*models.Question, error)
-	GetByID(id int) (*models.Question, error)
-	List() ([]models.Question, error)
-	Delete(id int) error
+	Create(ctx context.Context, authorID, title, body string, tags []string) (*models.Question, error)
+	GetByID(ctx context.Context, id int) (*models.Question, error)
+	// Exists doesn't load the question. In a transaction it also keeps the question
+	// from being deleted until the transaction ends.
+	Exists(ctx context.Context, id int) (bool, error)
+	List(ctx context.Context, opts ListQuestionsOptions) ([]models.Question, *Cursor, error)
+	// Update returns ErrNotOwner unless userID is the author of the question.
+	Update(ctx context.Context, id int, userID string, changes QuestionChanges) (*models.Question, error)
+	ListRevisions(ctx context.Context, questionID int) ([]models.QuestionRevision, error)
+	Vote(ctx context.Context, questionID int, userID string, value int) (int, error)
+	AcceptAnswer(ctx context.Context, questionID, answerID int) error
+	UnacceptAnswer(ctx context.Context, questionID int) error
+	// Delete moves the question to the trash, its answers are hidden along with it.
+	// It returns ErrNotOwner unless userID is the author of the question or admin is set.
+	Delete(ctx context.Context, id int, userID string, admin bool) error
+	// GetDeleted returns ErrNotFound unless the question is in the trash.
+	GetDeleted(ctx context.Context, id int) (*models.Question, error)
+	Restore(ctx context.Context, id int) error
 }
 
 type AnswerRepository interface {
-	CreateAnswer(questionID int, userID, text string) (*models.Answer, error)
-	GetAnswerByID(id int) (*models.Answer, error)
-	DeleteAnswer(id int) error
+	CreateAnswer(ctx context.Context, questionID int, userID, body string) (*models.Answer, error)
+	GetAnswerByID(ctx context.Context, id int) (*models.Answer, error)
+	// ListAnswers puts the accepted answer first whatever the sort. It returns ErrNotFound when the question doesn't exist.
+	ListAnswers(ctx context.Context, questionID int, opts ListAnswersOptions) ([]models.Answer, *Cursor, error)
+	// UpdateAnswer returns ErrNotOwner unless userID wrote the answer.
+	UpdateAnswer(ctx context.Context, id int, userID, body string) (*models.Answer, error)
+	ListAnswerRevisions(ctx context.Context, answerID int) ([]models.AnswerRevision, error)
+	VoteAnswer(ctx context.Context, answerID int, userID string, value int) (int, error)
+	// DeleteAnswer returns ErrNotOwner unless userID wrote the answer or admin is set.
+	DeleteAnswer(ctx context.Context, id int, userID string, admin bool) error
+	// GetDeletedAnswer returns ErrNotFound unless the answer itself is in the trash and its question isn't.
+	GetDeletedAnswer(ctx context.Context, id int) (*models.Answer, error)
+	RestoreAnswer(ctx context.Context, id int) error
+}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, id, displayName, bio string) (*models.User, error)
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	ListAnswersByUser(ctx context.Context, userID string, limit int, cursor *Cursor) ([]models.Answer, *Cursor, error)
+}
+
+// Comments are listed oldest first, replies come after their parent.
+type CommentRepository interface {
+	CreateComment(ctx context.Context, target CommentTarget, parentID *int, userID, body string) (*models.Comment, error)
+	GetCommentByID(ctx context.Context, id int) (*models.Comment, error)
+	ListComments(ctx context.Context, target CommentTarget) ([]models.Comment, error)
+	// ListQuestionComments returns comments on the question and on all of its answers.
+	ListQuestionComments(ctx context.Context, questionID int) ([]models.Comment, error)
+	DeleteComment(ctx context.Context, id int) error
+}
+
+// TrashRepository lists deleted questions and answers, the most recently deleted first.
+// Answers of a deleted question are not listed, they are in the trash as part of it.
+type TrashRepository interface {
+	ListDeletedQuestions(ctx context.Context, limit int, cursor *Cursor) ([]models.Question, *Cursor, error)
+	ListDeletedAnswers(ctx context.Context, limit int, cursor *Cursor) ([]models.Answer, *Cursor, error)
+	// PurgeDeleted removes for good what was deleted before the given time and returns how many questions and answers it removed.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, int64, error)
+}
+
+// AuditFilter narrows down the audit log, empty and nil fields match any event.
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	From       *time.Time // Inclusive
+	To         *time.Time // Exclusive
+}
+
+// AuditRepository reads the audit log, the newest events first. Events are written by the changes themselves.
+type AuditRepository interface {
+	ListAuditEvents(ctx context.Context, filter AuditFilter, limit int, cursor *Cursor) ([]models.AuditEvent, *Cursor, error)
+}
+
+// DeliveryResult is the outcome of an attempt to deliver a webhook. A failed delivery is attempted again
+// at NextAttemptAt, nil when it has run out of attempts.
+type DeliveryResult struct {
+	Delivered     bool
+	StatusCode    *int // Nil when the endpoint couldn't be reached
+	Error         string
+	NextAttemptAt *time.Time
+}
+
+// WebhookRepository manages webhook subscriptions and their delivery queue.
+// Deliveries are queued by the changes themselves, along with their audit events.
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, url string, events []string, secret, createdBy string) (*models.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error)
+	// DeleteWebhook also removes the deliveries of the subscription, including the pending ones.
+	DeleteWebhook(ctx context.Context, id int) error
+	// ListWebhookDeliveries returns the newest deliveries first, ErrNotFound when the subscription doesn't exist.
+	ListWebhookDeliveries(ctx context.Context, subscriptionID, limit int, cursor *Cursor) ([]models.WebhookDelivery, *Cursor, error)
+	// ClaimDeliveries returns up to limit pending deliveries that are due, with their subscription, and postpones them by lease
+	// so that other replicas skip them while they are sent. If the sender dies, they are sent again once the lease is over.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	FinishDelivery(ctx context.Context, id int, result DeliveryResult) error
+}
+
+type TagRepository interface {
+	ListTags(ctx context.Context, limit int) ([]models.Tag, error)
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error)
+}
+
+// Repositories run their queries in the transaction they were given for.
+type Repositories struct {
+	Questions QuestionRepository
+	Answers   AnswerRepository
+}
+
+// Transactor runs fn in a transaction, it is committed when fn returns nil and rolled back otherwise.
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(tx Repositories) error) error
 }
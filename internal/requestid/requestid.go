@@ -0,0 +1,43 @@
+// Package requestid carries the ID correlating responses and log lines of a request.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+)
+
+const Header = "X-Request-ID"
+
+const maxLength = 128
+
+type idKey struct{}
+
+// New generates a random ID.
+func New() string {
+	return rand.Text()
+}
+
+// Valid accepts IDs assigned by clients or proxies: up to 128 printable ASCII characters without spaces,
+// so that they can be echoed in a header and logged as is.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(idKey{}).(string)
+	return id, ok
+}
@@ -0,0 +1,36 @@
+package requestid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		valid bool
+	}{
+		{name: "uuid", id: "0b6f2c1e-9a7d-4c1b-8f7e-3d2a1c0b9e8f", valid: true},
+		{name: "generated", id: New(), valid: true},
+		{name: "empty", id: "", valid: false},
+		{name: "too long", id: strings.Repeat("a", 129), valid: false},
+		{name: "space", id: "req 42", valid: false},
+		{name: "newline", id: "req\n42", valid: false},
+		{name: "non ascii", id: "запрос", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.id); got != tt.valid {
+				t.Errorf("expected %v, got %v", tt.valid, got)
+			}
+		})
+	}
+}
+
+func TestNewIsUnique(t *testing.T) {
+	if New() == New() {
+		t.Error("expected different IDs")
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+type AnswerService interface {
+	Create(ctx context.Context, questionID int, userID, body string) (*models.Answer, error)
+	Get(ctx context.Context, id int) (*models.Answer, error)
+	List(ctx context.Context, questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error)
+	Update(ctx context.Context, id int, userID, body string) (*models.Answer, error)
+	ListRevisions(ctx context.Context, id int) ([]models.AnswerRevision, error)
+	Vote(ctx context.Context, id int, userID string, value int) (int, error)
+	Delete(ctx context.Context, id int, userID string, admin bool) error
+	Restore(ctx context.Context, id int, userID string, admin bool) error
+}
+
+type answerService struct {
+	answers    repository.AnswerRepository
+	transactor repository.Transactor
+	metrics    Metrics
+}
+
+func NewAnswerService(answers repository.AnswerRepository, transactor repository.Transactor, metrics Metrics) AnswerService {
+	return &answerService{answers: answers, transactor: transactor, metrics: metrics}
+}
+
+// Create checks that the question exists in the same transaction that inserts the answer,
+// so the question can't be deleted in between.
+func (s *answerService) Create(ctx context.Context, questionID int, userID, body string) (*models.Answer, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, invalid("body", "Body cannot be empty")
+	}
+
+	var answer *models.Answer
+
+	err := s.transactor.WithTx(ctx, func(tx repository.Repositories) error {
+		exists, err := tx.Questions.Exists(ctx, questionID)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return ErrQuestionNotFound
+		}
+
+		answer, err = tx.Answers.CreateAnswer(ctx, questionID, userID, body)
+
+		return err
+	})
+	if err != nil {
+		switch repository.ViolatedConstraint(err) {
+		case repository.ConstraintAnswerQuestion:
+			// The question was deleted anyway, e.g. by a transaction that doesn't lock it.
+			return nil, fmt.Errorf("%w: %w", ErrQuestionNotFound, err)
+		case repository.ConstraintAnswerUser:
+			// The user was removed after the handler checked the registration.
+			return nil, fmt.Errorf("%w: %w", ErrUserNotRegistered, err)
+		}
+
+		return nil, err
+	}
+
+	s.metrics.AnswerCreated()
+
+	return answer, nil
+}
+
+func (s *answerService) Get(ctx context.Context, id int) (*models.Answer, error) {
+	answer, err := s.answers.GetAnswerByID(ctx, id)
+	if err != nil {
+		return nil, answerError(err)
+	}
+
+	return answer, nil
+}
+
+func (s *answerService) List(ctx context.Context, questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error) {
+	answers, next, err := s.answers.ListAnswers(ctx, questionID, opts)
+	if err != nil {
+		return nil, nil, questionError(err)
+	}
+
+	return answers, next, nil
+}
+
+// Update only lets the author of the answer change it.
+func (s *answerService) Update(ctx context.Context, id int, userID, body string) (*models.Answer, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, invalid("body", "Body cannot be empty")
+	}
+
+	// The author is checked in the transaction that changes the answer
+	answer, err := s.answers.UpdateAnswer(ctx, id, userID, body)
+	if err != nil {
+		return nil, answerError(err)
+	}
+
+	return answer, nil
+}
+
+func (s *answerService) ListRevisions(ctx context.Context, id int) ([]models.AnswerRevision, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.answers.ListAnswerRevisions(ctx, id)
+}
+
+// Vote returns the score of the answer after the vote, voting again replaces the previous vote.
+func (s *answerService) Vote(ctx context.Context, id int, userID string, value int) (int, error) {
+	if err := checkVote(value); err != nil {
+		return 0, err
+	}
+
+	score, err := s.answers.VoteAnswer(ctx, id, userID, value)
+	if err != nil {
+		return 0, answerError(err)
+	}
+
+	return score, nil
+}
+
+// Delete moves the answer to the trash, it can be restored until it is purged.
+// It is allowed to the author of the answer and to admins.
+func (s *answerService) Delete(ctx context.Context, id int, userID string, admin bool) error {
+	return answerError(s.answers.DeleteAnswer(ctx, id, userID, admin))
+}
+
+// Restore is allowed to the user who deleted the answer and to admins.
+func (s *answerService) Restore(ctx context.Context, id int, userID string, admin bool) error {
+	answer, err := s.answers.GetDeletedAnswer(ctx, id)
+	if err != nil {
+		return answerError(err)
+	}
+
+	if !admin && (answer.DeletedBy == nil || *answer.DeletedBy != userID) {
+		return ErrNotDeleter
+	}
+
+	return answerError(s.answers.RestoreAnswer(ctx, id))
+}
+
+func answerError(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %w", ErrAnswerNotFound, err)
+	}
+
+	return ownerError(err)
+}
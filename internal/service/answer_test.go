@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+type mockAnswerRepo struct {
+	createAnswerFunc     func(questionID int, userID, body string) (*models.Answer, error)
+	getAnswerByIDFunc    func(id int) (*models.Answer, error)
+	listAnswersFunc      func(questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error)
+	updateAnswerFunc     func(id int, userID, body string) (*models.Answer, error)
+	voteAnswerFunc       func(answerID int, userID string, value int) (int, error)
+	deleteAnswerFunc     func(id int, userID string, admin bool) error
+	getDeletedAnswerFunc func(id int) (*models.Answer, error)
+}
+
+func (m *mockAnswerRepo) CreateAnswer(ctx context.Context, questionID int, userID, body string) (*models.Answer, error) {
+	if m.createAnswerFunc != nil {
+		return m.createAnswerFunc(questionID, userID, body)
+	}
+	return &models.Answer{ID: 1, QuestionID: questionID, UserID: userID, Body: body}, nil
+}
+
+func (m *mockAnswerRepo) GetAnswerByID(ctx context.Context, id int) (*models.Answer, error) {
+	if m.getAnswerByIDFunc != nil {
+		return m.getAnswerByIDFunc(id)
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockAnswerRepo) ListAnswers(ctx context.Context, questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error) {
+	if m.listAnswersFunc != nil {
+		return m.listAnswersFunc(questionID, opts)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockAnswerRepo) UpdateAnswer(ctx context.Context, id int, userID, body string) (*models.Answer, error) {
+	if m.updateAnswerFunc != nil {
+		return m.updateAnswerFunc(id, userID, body)
+	}
+	return nil, nil
+}
+
+func (m *mockAnswerRepo) ListAnswerRevisions(ctx context.Context, answerID int) ([]models.AnswerRevision, error) {
+	return nil, nil
+}
+
+func (m *mockAnswerRepo) VoteAnswer(ctx context.Context, answerID int, userID string, value int) (int, error) {
+	if m.voteAnswerFunc != nil {
+		return m.voteAnswerFunc(answerID, userID, value)
+	}
+	return value, nil
+}
+
+func (m *mockAnswerRepo) DeleteAnswer(ctx context.Context, id int, userID string, admin bool) error {
+	if m.deleteAnswerFunc != nil {
+		return m.deleteAnswerFunc(id, userID, admin)
+	}
+	return nil
+}
+
+func (m *mockAnswerRepo) GetDeletedAnswer(ctx context.Context, id int) (*models.Answer, error) {
+	if m.getDeletedAnswerFunc != nil {
+		return m.getDeletedAnswerFunc(id)
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockAnswerRepo) RestoreAnswer(ctx context.Context, id int) error {
+	return nil
+}
+
+// mockTransactor runs fn right away, the mocks have nothing to roll back.
+type mockTransactor struct {
+	questions *mockQuestionRepo
+	answers   *mockAnswerRepo
+}
+
+func (m *mockTransactor) WithTx(ctx context.Context, fn func(tx repository.Repositories) error) error {
+	return fn(repository.Repositories{Questions: m.questions, Answers: m.answers})
+}
+
+func TestAnswerService_Create(t *testing.T) {
+	created := false
+	answers := &mockAnswerRepo{
+		createAnswerFunc: func(questionID int, userID, body string) (*models.Answer, error) {
+			switch questionID {
+			case 998:
+				return nil, &repository.ForeignKeyError{Constraint: repository.ConstraintAnswerQuestion, Err: errors.New("question")}
+			case 997:
+				return nil, &repository.ForeignKeyError{Constraint: repository.ConstraintAnswerUser, Err: errors.New("user")}
+			case 996:
+				return nil, &repository.ForeignKeyError{Constraint: "fk_something_else", Err: errors.New("other")}
+			}
+			created = true
+			return &models.Answer{ID: 1, QuestionID: questionID, UserID: userID, Body: body}, nil
+		},
+	}
+	questions := &mockQuestionRepo{
+		existsFunc: func(id int) (bool, error) {
+			return id != 999, nil
+		},
+	}
+	metrics := &mockMetrics{}
+	s := NewAnswerService(answers, &mockTransactor{questions: questions, answers: answers}, metrics)
+
+	tests := []struct {
+		name           string
+		questionID     int
+		body           string
+		expectedErr    error
+		expectedFields []string
+	}{
+		{name: "valid", questionID: 1, body: "Some answer"},
+		{name: "empty body", questionID: 1, body: "", expectedFields: []string{"body"}},
+		{name: "whitespace only body", questionID: 1, body: "   ", expectedFields: []string{"body"}},
+		{name: "question not found", questionID: 999, body: "Some answer", expectedErr: ErrQuestionNotFound},
+		{name: "question deleted concurrently", questionID: 998, body: "Some answer", expectedErr: ErrQuestionNotFound},
+		{name: "user removed concurrently", questionID: 997, body: "Some answer", expectedErr: ErrUserNotRegistered},
+		{name: "other foreign key", questionID: 996, body: "Some answer", expectedErr: repository.ErrForeignKeyViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = false
+			metrics.answersCreated = 0
+
+			_, err := s.Create(context.Background(), tt.questionID, "user-123", tt.body)
+
+			if tt.expectedFields != nil {
+				if fields := invalidFields(err); !slices.Equal(fields, tt.expectedFields) {
+					t.Errorf("expected invalid fields %v, got %v", tt.expectedFields, err)
+				}
+			} else if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if created != (err == nil) {
+				t.Errorf("unexpected repository create call: %v", created)
+			}
+
+			if (metrics.answersCreated == 1) != (err == nil) {
+				t.Errorf("expected only created answers to be counted, got %d", metrics.answersCreated)
+			}
+		})
+	}
+}
+
+func TestAnswerService_OnlyAuthorCanEdit(t *testing.T) {
+	updated := false
+	answers := &mockAnswerRepo{
+		updateAnswerFunc: func(id int, userID, body string) (*models.Answer, error) {
+			if userID != "author" {
+				return nil, repository.ErrNotOwner
+			}
+			updated = true
+			return &models.Answer{ID: id, QuestionID: 1, UserID: "author", Body: body}, nil
+		},
+	}
+	s := NewAnswerService(answers, &mockTransactor{questions: &mockQuestionRepo{}, answers: answers}, &mockMetrics{})
+
+	tests := []struct {
+		name        string
+		userID      string
+		expectedErr error
+	}{
+		{name: "another user", userID: "intruder", expectedErr: ErrNotAuthor},
+		{name: "author", userID: "author"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated = false
+
+			_, err := s.Update(context.Background(), 5, tt.userID, "New text")
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if updated != (tt.expectedErr == nil) {
+				t.Errorf("unexpected repository update call: %v", updated)
+			}
+		})
+	}
+
+	if _, err := s.Update(context.Background(), 404, "author", "   "); !slices.Equal(invalidFields(err), []string{"body"}) {
+		t.Errorf("expected invalid body, got %v", err)
+	}
+}
+
+func TestAnswerService_Vote(t *testing.T) {
+	answers := &mockAnswerRepo{
+		voteAnswerFunc: func(answerID int, userID string, value int) (int, error) {
+			if answerID == 404 {
+				return 0, repository.ErrNotFound
+			}
+			return 10 + value, nil
+		},
+	}
+	s := NewAnswerService(answers, &mockTransactor{questions: &mockQuestionRepo{}, answers: answers}, &mockMetrics{})
+
+	tests := []struct {
+		name          string
+		id            int
+		value         int
+		expectedErr   error
+		expectedScore int
+		invalid       bool
+	}{
+		{name: "upvote", id: 1, value: 1, expectedScore: 11},
+		{name: "downvote", id: 1, value: -1, expectedScore: 9},
+		{name: "zero", id: 1, value: 0, invalid: true},
+		{name: "more than one", id: 1, value: 2, invalid: true},
+		{name: "unknown answer", id: 404, value: 1, expectedErr: ErrAnswerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, err := s.Vote(context.Background(), tt.id, "user-123", tt.value)
+
+			if tt.invalid {
+				if !slices.Equal(invalidFields(err), []string{"value"}) {
+					t.Errorf("expected invalid value, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if score != tt.expectedScore {
+				t.Errorf("expected score %d, got %d", tt.expectedScore, score)
+			}
+		})
+	}
+}
+
+func TestAnswerService_ListQuestionNotFound(t *testing.T) {
+	answers := &mockAnswerRepo{
+		listAnswersFunc: func(questionID int, opts repository.ListAnswersOptions) ([]models.Answer, *repository.Cursor, error) {
+			return nil, nil, repository.ErrNotFound
+		},
+	}
+	s := NewAnswerService(answers, &mockTransactor{answers: answers}, &mockMetrics{})
+
+	_, _, err := s.List(context.Background(), 999, repository.ListAnswersOptions{Limit: 20, Sort: repository.AnswerSortScore})
+
+	if !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("expected ErrQuestionNotFound, got %v", err)
+	}
+}
+
+func TestAnswerService_Delete(t *testing.T) {
+	var deletedBy string
+	answers := &mockAnswerRepo{
+		deleteAnswerFunc: func(id int, userID string, admin bool) error {
+			if id == 404 {
+				return repository.ErrNotFound
+			}
+			if !admin && userID != "author" {
+				return repository.ErrNotOwner
+			}
+			deletedBy = userID
+			return nil
+		},
+	}
+	s := NewAnswerService(answers, &mockTransactor{answers: answers}, &mockMetrics{})
+
+	tests := []struct {
+		name        string
+		id          int
+		userID      string
+		admin       bool
+		expectedErr error
+	}{
+		{name: "author", id: 1, userID: "author"},
+		{name: "admin", id: 1, userID: "moderator", admin: true},
+		{name: "someone else", id: 1, userID: "intruder", expectedErr: ErrNotAuthor},
+		{name: "missing answer", id: 404, userID: "author", expectedErr: ErrAnswerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletedBy = ""
+
+			err := s.Delete(context.Background(), tt.id, tt.userID, tt.admin)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if (deletedBy == tt.userID) != (tt.expectedErr == nil) {
+				t.Errorf("unexpected repository delete call by %q", deletedBy)
+			}
+		})
+	}
+}
+
+func TestAnswerService_Restore(t *testing.T) {
+	deleter := "deleter"
+	answers := &mockAnswerRepo{
+		getDeletedAnswerFunc: func(id int) (*models.Answer, error) {
+			if id == 404 {
+				return nil, repository.ErrNotFound
+			}
+			return &models.Answer{ID: id, UserID: "author", DeletedBy: &deleter}, nil
+		},
+	}
+	s := NewAnswerService(answers, &mockTransactor{answers: answers}, &mockMetrics{})
+
+	tests := []struct {
+		name        string
+		id          int
+		userID      string
+		admin       bool
+		expectedErr error
+	}{
+		{name: "user who deleted it", id: 1, userID: "deleter"},
+		{name: "admin", id: 1, userID: "moderator", admin: true},
+		{name: "author of the answer", id: 1, userID: "author", expectedErr: ErrNotDeleter},
+		{name: "not in the trash", id: 404, userID: "deleter", expectedErr: ErrAnswerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Restore(context.Background(), tt.id, tt.userID, tt.admin)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
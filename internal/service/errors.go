@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrAnswerNotFound   = errors.New("answer not found")
+	// ErrNotAuthor is returned when a user changes something they didn't write.
+	ErrNotAuthor = errors.New("only the author can do this")
+	// ErrNotDeleter is returned when a user other than the one who deleted something restores it.
+	ErrNotDeleter      = errors.New("only the user who deleted it or an admin can restore it")
+	ErrNothingToUpdate = errors.New("nothing to update")
+	// ErrUserNotRegistered is returned when the user hasn't registered with POST /users.
+	ErrUserNotRegistered = errors.New("user is not registered")
+)
+
+// FieldError is an invalid input field, Message is meant to be shown to the user.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists all invalid fields of the input at once.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + ": " + f.Message
+	}
+
+	return "invalid input: " + strings.Join(messages, "; ")
+}
+
+func invalid(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
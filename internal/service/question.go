@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+const maxTitleLength = 150
+
+type QuestionService interface {
+	Create(ctx context.Context, authorID, title, body string, tags []string) (*models.Question, error)
+	Get(ctx context.Context, id int) (*models.Question, error)
+	List(ctx context.Context, opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error)
+	Update(ctx context.Context, id int, userID string, changes repository.QuestionChanges) (*models.Question, error)
+	ListRevisions(ctx context.Context, id int) ([]models.QuestionRevision, error)
+	Vote(ctx context.Context, id int, userID string, value int) (int, error)
+	AcceptAnswer(ctx context.Context, id, answerID int, userID string) error
+	UnacceptAnswer(ctx context.Context, id int, userID string) error
+	Delete(ctx context.Context, id int, userID string, admin bool) error
+	Restore(ctx context.Context, id int, userID string, admin bool) error
+}
+
+type questionService struct {
+	questions repository.QuestionRepository
+	metrics   Metrics
+}
+
+func NewQuestionService(questions repository.QuestionRepository, metrics Metrics) QuestionService {
+	return &questionService{questions: questions, metrics: metrics}
+}
+
+// Create trims the title and normalizes the tags before storing the question.
+func (s *questionService) Create(ctx context.Context, authorID, title, body string, tags []string) (*models.Question, error) {
+	var invalid ValidationError
+
+	title, err := normalizeTitle(title)
+	if err != nil {
+		invalid.Fields = append(invalid.Fields, FieldError{Field: "title", Message: err.Error()})
+	}
+
+	if strings.TrimSpace(body) == "" {
+		invalid.Fields = append(invalid.Fields, FieldError{Field: "body", Message: "Body cannot be empty"})
+	}
+
+	tags, err = normalizeTags(tags)
+	if err != nil {
+		invalid.Fields = append(invalid.Fields, FieldError{Field: "tags", Message: err.Error()})
+	}
+
+	if len(invalid.Fields) > 0 {
+		return nil, &invalid
+	}
+
+	question, err := s.questions.Create(ctx, authorID, title, body, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.QuestionCreated()
+
+	return question, nil
+}
+
+func (s *questionService) Get(ctx context.Context, id int) (*models.Question, error) {
+	question, err := s.questions.GetByID(ctx, id)
+	if err != nil {
+		return nil, questionError(err)
+	}
+
+	return question, nil
+}
+
+// List normalizes the tag filter the same way tags are normalized on create.
+func (s *questionService) List(ctx context.Context, opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error) {
+	if len(opts.Tags) > 0 {
+		tags, err := normalizeTagFilter(opts.Tags)
+		if err != nil {
+			return nil, nil, invalid("tag", err.Error())
+		}
+		opts.Tags = tags
+	}
+
+	return s.questions.List(ctx, opts)
+}
+
+// Update only lets the author of the question change it, and only changes the fields that are set.
+func (s *questionService) Update(ctx context.Context, id int, userID string, changes repository.QuestionChanges) (*models.Question, error) {
+	if changes.Title == nil && changes.Body == nil && changes.Tags == nil {
+		return nil, ErrNothingToUpdate
+	}
+
+	var invalid ValidationError
+
+	if changes.Title != nil {
+		title, err := normalizeTitle(*changes.Title)
+		if err != nil {
+			invalid.Fields = append(invalid.Fields, FieldError{Field: "title", Message: err.Error()})
+		}
+		changes.Title = &title
+	}
+
+	if changes.Body != nil && strings.TrimSpace(*changes.Body) == "" {
+		invalid.Fields = append(invalid.Fields, FieldError{Field: "body", Message: "Body cannot be empty"})
+	}
+
+	if changes.Tags != nil {
+		tags, err := normalizeTags(*changes.Tags)
+		if err != nil {
+			invalid.Fields = append(invalid.Fields, FieldError{Field: "tags", Message: err.Error()})
+		}
+		changes.Tags = &tags
+	}
+
+	if len(invalid.Fields) > 0 {
+		return nil, &invalid
+	}
+
+	// The author is checked in the transaction that changes the question
+	question, err := s.questions.Update(ctx, id, userID, changes)
+	if err != nil {
+		return nil, questionError(err)
+	}
+
+	return question, nil
+}
+
+func (s *questionService) ListRevisions(ctx context.Context, id int) ([]models.QuestionRevision, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.questions.ListRevisions(ctx, id)
+}
+
+// Vote returns the score of the question after the vote, voting again replaces the previous vote.
+func (s *questionService) Vote(ctx context.Context, id int, userID string, value int) (int, error) {
+	if err := checkVote(value); err != nil {
+		return 0, err
+	}
+
+	score, err := s.questions.Vote(ctx, id, userID, value)
+	if err != nil {
+		return 0, questionError(err)
+	}
+
+	return score, nil
+}
+
+// AcceptAnswer is only allowed to the author of the question.
+// Accepting another answer replaces the previous one.
+func (s *questionService) AcceptAnswer(ctx context.Context, id, answerID int, userID string) error {
+	if _, err := s.ownQuestion(ctx, id, userID); err != nil {
+		return err
+	}
+
+	// Also not found when the answer belongs to another question
+	if err := s.questions.AcceptAnswer(ctx, id, answerID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %w", ErrAnswerNotFound, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *questionService) UnacceptAnswer(ctx context.Context, id int, userID string) error {
+	if _, err := s.ownQuestion(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return questionError(s.questions.UnacceptAnswer(ctx, id))
+}
+
+// Delete moves the question to the trash, it can be restored until it is purged.
+// It is allowed to the author of the question and to admins.
+func (s *questionService) Delete(ctx context.Context, id int, userID string, admin bool) error {
+	return questionError(s.questions.Delete(ctx, id, userID, admin))
+}
+
+// Restore is allowed to the user who deleted the question and to admins.
+func (s *questionService) Restore(ctx context.Context, id int, userID string, admin bool) error {
+	question, err := s.questions.GetDeleted(ctx, id)
+	if err != nil {
+		return questionError(err)
+	}
+
+	if !admin && (question.DeletedBy == nil || *question.DeletedBy != userID) {
+		return ErrNotDeleter
+	}
+
+	return questionError(s.questions.Restore(ctx, id))
+}
+
+func (s *questionService) ownQuestion(ctx context.Context, id int, userID string) (*models.Question, error) {
+	question, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if question.AuthorID == nil || *question.AuthorID != userID {
+		return nil, ErrNotAuthor
+	}
+
+	return question, nil
+}
+
+func normalizeTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+
+	if title == "" {
+		return "", errors.New("title cannot be empty")
+	}
+
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		return "", fmt.Errorf("title must be at most %d characters", maxTitleLength)
+	}
+
+	return title, nil
+}
+
+func checkVote(value int) error {
+	if value != 1 && value != -1 {
+		return invalid("value", "Value must be 1 or -1")
+	}
+
+	return nil
+}
+
+// questionError keeps the repository error as the cause for logs.
+func questionError(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %w", ErrQuestionNotFound, err)
+	}
+
+	return ownerError(err)
+}
+
+func ownerError(err error) error {
+	if errors.Is(err, repository.ErrNotOwner) {
+		return fmt.Errorf("%w: %w", ErrNotAuthor, err)
+	}
+
+	return err
+}
@@ -0,0 +1,484 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+type mockQuestionRepo struct {
+	createFunc       func(authorID, title, body string, tags []string) (*models.Question, error)
+	getByIDFunc      func(id int) (*models.Question, error)
+	existsFunc       func(id int) (bool, error)
+	listFunc         func(opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error)
+	updateFunc       func(id int, userID string, changes repository.QuestionChanges) (*models.Question, error)
+	voteFunc         func(questionID int, userID string, value int) (int, error)
+	acceptAnswerFunc func(questionID, answerID int) error
+	deleteFunc       func(id int, userID string, admin bool) error
+	getDeletedFunc   func(id int) (*models.Question, error)
+	restoreFunc      func(id int) error
+}
+
+func (m *mockQuestionRepo) Create(ctx context.Context, authorID, title, body string, tags []string) (*models.Question, error) {
+	if m.createFunc != nil {
+		return m.createFunc(authorID, title, body, tags)
+	}
+	return &models.Question{ID: 1, AuthorID: &authorID, Title: title, Body: body}, nil
+}
+
+func (m *mockQuestionRepo) GetByID(ctx context.Context, id int) (*models.Question, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(id)
+	}
+	return &models.Question{ID: id}, nil
+}
+
+func (m *mockQuestionRepo) Exists(ctx context.Context, id int) (bool, error) {
+	if m.existsFunc != nil {
+		return m.existsFunc(id)
+	}
+	return true, nil
+}
+
+func (m *mockQuestionRepo) List(ctx context.Context, opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error) {
+	if m.listFunc != nil {
+		return m.listFunc(opts)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockQuestionRepo) Update(ctx context.Context, id int, userID string, changes repository.QuestionChanges) (*models.Question, error) {
+	if m.updateFunc != nil {
+		return m.updateFunc(id, userID, changes)
+	}
+	return &models.Question{ID: id}, nil
+}
+
+func (m *mockQuestionRepo) ListRevisions(ctx context.Context, questionID int) ([]models.QuestionRevision, error) {
+	return nil, nil
+}
+
+func (m *mockQuestionRepo) Vote(ctx context.Context, questionID int, userID string, value int) (int, error) {
+	if m.voteFunc != nil {
+		return m.voteFunc(questionID, userID, value)
+	}
+	return value, nil
+}
+
+func (m *mockQuestionRepo) AcceptAnswer(ctx context.Context, questionID, answerID int) error {
+	if m.acceptAnswerFunc != nil {
+		return m.acceptAnswerFunc(questionID, answerID)
+	}
+	return nil
+}
+
+func (m *mockQuestionRepo) UnacceptAnswer(ctx context.Context, questionID int) error {
+	return nil
+}
+
+func (m *mockQuestionRepo) Delete(ctx context.Context, id int, userID string, admin bool) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id, userID, admin)
+	}
+	return nil
+}
+
+func (m *mockQuestionRepo) GetDeleted(ctx context.Context, id int) (*models.Question, error) {
+	if m.getDeletedFunc != nil {
+		return m.getDeletedFunc(id)
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockQuestionRepo) Restore(ctx context.Context, id int) error {
+	if m.restoreFunc != nil {
+		return m.restoreFunc(id)
+	}
+	return nil
+}
+
+type mockMetrics struct {
+	questionsCreated int
+	answersCreated   int
+}
+
+func (m *mockMetrics) QuestionCreated() {
+	m.questionsCreated++
+}
+
+func (m *mockMetrics) AnswerCreated() {
+	m.answersCreated++
+}
+
+func invalidFields(err error) []string {
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		return nil
+	}
+
+	fields := make([]string, len(invalid.Fields))
+	for i, f := range invalid.Fields {
+		fields[i] = f.Field
+	}
+
+	return fields
+}
+
+func TestQuestionService_CreateValidation(t *testing.T) {
+	created := false
+	questions := &mockQuestionRepo{
+		createFunc: func(authorID, title, body string, tags []string) (*models.Question, error) {
+			created = true
+			return &models.Question{ID: 1}, nil
+		},
+	}
+	s := NewQuestionService(questions, &mockMetrics{})
+
+	tests := []struct {
+		name           string
+		title          string
+		body           string
+		tags           []string
+		expectedFields []string
+	}{
+		{name: "empty title", title: "", body: "Details", expectedFields: []string{"title"}},
+		{name: "whitespace only title", title: "   ", body: "Details", expectedFields: []string{"title"}},
+		{name: "title too long", title: strings.Repeat("я", maxTitleLength+1), body: "Details", expectedFields: []string{"title"}},
+		{name: "empty body", title: "What is Go?", body: "", expectedFields: []string{"body"}},
+		{name: "whitespace only body", title: "What is Go?", body: "   ", expectedFields: []string{"body"}},
+		{name: "invalid tag", title: "What is Go?", body: "Details", tags: []string{"<b>"}, expectedFields: []string{"tags"}},
+		{name: "too many tags", title: "What is Go?", body: "Details", tags: []string{"a", "b", "c", "d", "e", "f"}, expectedFields: []string{"tags"}},
+		{name: "all invalid fields reported", title: "", body: "", tags: []string{"<b>"}, expectedFields: []string{"title", "body", "tags"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = false
+
+			_, err := s.Create(context.Background(), "user-123", tt.title, tt.body, tt.tags)
+
+			if fields := invalidFields(err); !slices.Equal(fields, tt.expectedFields) {
+				t.Errorf("expected invalid fields %v, got %v (%v)", tt.expectedFields, fields, err)
+			}
+
+			if created {
+				t.Error("expected invalid question not to be stored")
+			}
+		})
+	}
+}
+
+func TestQuestionService_CreateNormalizes(t *testing.T) {
+	var gotTitle string
+	var gotTags []string
+	questions := &mockQuestionRepo{
+		createFunc: func(authorID, title, body string, tags []string) (*models.Question, error) {
+			gotTitle, gotTags = title, tags
+			return &models.Question{ID: 1, Title: title, Body: body}, nil
+		},
+	}
+	metrics := &mockMetrics{}
+	s := NewQuestionService(questions, metrics)
+
+	_, err := s.Create(context.Background(), "user-123", "  What is Go? ", "Details", []string{"Go", "go", "Unit Testing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if metrics.questionsCreated != 1 {
+		t.Errorf("expected the question to be counted once, got %d", metrics.questionsCreated)
+	}
+
+	if gotTitle != "What is Go?" {
+		t.Errorf("expected trimmed title, got %q", gotTitle)
+	}
+
+	if !slices.Equal(gotTags, []string{"go", "unit-testing"}) {
+		t.Errorf("expected normalized and deduplicated tags, got %v", gotTags)
+	}
+}
+
+func TestQuestionService_UpdateValidation(t *testing.T) {
+	s := NewQuestionService(&mockQuestionRepo{}, &mockMetrics{})
+
+	empty, blank, title := "", "   ", "What is Go?"
+
+	tests := []struct {
+		name           string
+		changes        repository.QuestionChanges
+		expectedFields []string
+	}{
+		{name: "empty title", changes: repository.QuestionChanges{Title: &empty}, expectedFields: []string{"title"}},
+		{name: "whitespace only title", changes: repository.QuestionChanges{Title: &blank}, expectedFields: []string{"title"}},
+		{name: "empty body", changes: repository.QuestionChanges{Body: &empty}, expectedFields: []string{"body"}},
+		{name: "whitespace only body", changes: repository.QuestionChanges{Title: &title, Body: &blank}, expectedFields: []string{"body"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Update(context.Background(), 1, "author", tt.changes)
+
+			if fields := invalidFields(err); !slices.Equal(fields, tt.expectedFields) {
+				t.Errorf("expected invalid fields %v, got %v (%v)", tt.expectedFields, fields, err)
+			}
+		})
+	}
+
+	if _, err := s.Update(context.Background(), 1, "author", repository.QuestionChanges{}); !errors.Is(err, ErrNothingToUpdate) {
+		t.Errorf("expected ErrNothingToUpdate, got %v", err)
+	}
+}
+
+func TestQuestionService_OnlyAuthorCanEdit(t *testing.T) {
+	updated := false
+	authorID := "author"
+	questions := &mockQuestionRepo{
+		updateFunc: func(id int, userID string, changes repository.QuestionChanges) (*models.Question, error) {
+			if userID != authorID {
+				return nil, repository.ErrNotOwner
+			}
+			updated = true
+			return &models.Question{ID: id, AuthorID: &authorID, Title: *changes.Title}, nil
+		},
+	}
+	s := NewQuestionService(questions, &mockMetrics{})
+
+	title := "What is Go, really?"
+
+	tests := []struct {
+		name        string
+		userID      string
+		expectedErr error
+	}{
+		{name: "another user", userID: "intruder", expectedErr: ErrNotAuthor},
+		{name: "author", userID: "author"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated = false
+
+			_, err := s.Update(context.Background(), 1, tt.userID, repository.QuestionChanges{Title: &title})
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if updated != (tt.expectedErr == nil) {
+				t.Errorf("unexpected repository update call: %v", updated)
+			}
+		})
+	}
+}
+
+func TestQuestionService_ListNormalizesTagFilter(t *testing.T) {
+	var gotOpts repository.ListQuestionsOptions
+	questions := &mockQuestionRepo{
+		listFunc: func(opts repository.ListQuestionsOptions) ([]models.Question, *repository.Cursor, error) {
+			gotOpts = opts
+			return nil, nil, nil
+		},
+	}
+	s := NewQuestionService(questions, &mockMetrics{})
+
+	if _, _, err := s.List(context.Background(), repository.ListQuestionsOptions{Tags: []string{"Go", "PostgreSQL"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(gotOpts.Tags, []string{"go", "postgresql"}) {
+		t.Errorf("expected normalized tags, got %v", gotOpts.Tags)
+	}
+
+	_, _, err := s.List(context.Background(), repository.ListQuestionsOptions{Tags: []string{"<b>"}})
+	if fields := invalidFields(err); !slices.Equal(fields, []string{"tag"}) {
+		t.Errorf("expected invalid tag parameter, got %v", err)
+	}
+
+	// More tags than a question can have
+	many := []string{"go", "rust", "c", "java", "python", "ruby"}
+	if _, _, err := s.List(context.Background(), repository.ListQuestionsOptions{Tags: many}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(gotOpts.Tags, many) {
+		t.Errorf("expected all %d tags in the filter, got %v", len(many), gotOpts.Tags)
+	}
+
+	tooMany := make([]string, maxTagsPerFilter+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+	_, _, err = s.List(context.Background(), repository.ListQuestionsOptions{Tags: tooMany})
+	if fields := invalidFields(err); !slices.Equal(fields, []string{"tag"}) {
+		t.Errorf("expected too many tags to be rejected, got %v", err)
+	}
+}
+
+func TestQuestionService_AcceptAnswer(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		answerID    int
+		expectedErr error
+	}{
+		{name: "author accepts", userID: "asker", answerID: 11},
+		{name: "someone else accepts", userID: "intruder", answerID: 11, expectedErr: ErrNotAuthor},
+		{name: "answer of another question", userID: "asker", answerID: 99, expectedErr: ErrAnswerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accepted := 0
+			authorID := "asker"
+			questions := &mockQuestionRepo{
+				getByIDFunc: func(id int) (*models.Question, error) {
+					return &models.Question{ID: id, AuthorID: &authorID}, nil
+				},
+				acceptAnswerFunc: func(questionID, answerID int) error {
+					// Only answers 10 and 11 belong to the question
+					if answerID != 10 && answerID != 11 {
+						return repository.ErrNotFound
+					}
+					accepted = answerID
+					return nil
+				},
+			}
+			s := NewQuestionService(questions, &mockMetrics{})
+
+			err := s.AcceptAnswer(context.Background(), 1, tt.answerID, tt.userID)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if (accepted != 0) != (tt.expectedErr == nil) {
+				t.Errorf("unexpected repository accept call with answer %d", accepted)
+			}
+		})
+	}
+}
+
+func TestQuestionService_NotFound(t *testing.T) {
+	questions := &mockQuestionRepo{
+		getByIDFunc: func(id int) (*models.Question, error) {
+			return nil, repository.ErrNotFound
+		},
+		voteFunc: func(questionID int, userID string, value int) (int, error) {
+			return 0, repository.ErrNotFound
+		},
+		deleteFunc: func(id int, userID string, admin bool) error {
+			return repository.ErrNotFound
+		},
+	}
+	s := NewQuestionService(questions, &mockMetrics{})
+	ctx := context.Background()
+
+	if _, err := s.Get(ctx, 1); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("Get: expected ErrQuestionNotFound, got %v", err)
+	}
+
+	if _, err := s.ListRevisions(ctx, 1); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("ListRevisions: expected ErrQuestionNotFound, got %v", err)
+	}
+
+	if _, err := s.Vote(ctx, 1, "user-123", 1); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("Vote: expected ErrQuestionNotFound, got %v", err)
+	}
+
+	if err := s.Delete(ctx, 1, "user-123", false); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("Delete: expected ErrQuestionNotFound, got %v", err)
+	}
+
+	if err := s.Delete(ctx, 1, "moderator", true); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("Delete: expected ErrQuestionNotFound, got %v", err)
+	}
+
+	if err := s.Restore(ctx, 1, "user-123", true); !errors.Is(err, ErrQuestionNotFound) {
+		t.Errorf("Restore: expected ErrQuestionNotFound, got %v", err)
+	}
+}
+
+func TestQuestionService_Delete(t *testing.T) {
+	authorID := "author"
+
+	tests := []struct {
+		name        string
+		userID      string
+		admin       bool
+		expectedErr error
+	}{
+		{name: "author", userID: "author"},
+		{name: "admin", userID: "moderator", admin: true},
+		{name: "someone else", userID: "intruder", expectedErr: ErrNotAuthor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deletedBy string
+			questions := &mockQuestionRepo{
+				deleteFunc: func(id int, userID string, admin bool) error {
+					if !admin && userID != authorID {
+						return repository.ErrNotOwner
+					}
+					deletedBy = userID
+					return nil
+				},
+			}
+			s := NewQuestionService(questions, &mockMetrics{})
+
+			err := s.Delete(context.Background(), 1, tt.userID, tt.admin)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if (deletedBy == tt.userID) != (tt.expectedErr == nil) {
+				t.Errorf("unexpected repository delete call by %q", deletedBy)
+			}
+		})
+	}
+}
+
+func TestQuestionService_Restore(t *testing.T) {
+	deleter := "deleter"
+
+	tests := []struct {
+		name        string
+		userID      string
+		admin       bool
+		expectedErr error
+	}{
+		{name: "user who deleted it", userID: "deleter", expectedErr: nil},
+		{name: "admin", userID: "moderator", admin: true, expectedErr: nil},
+		{name: "someone else", userID: "author", expectedErr: ErrNotDeleter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restored := false
+			questions := &mockQuestionRepo{
+				getDeletedFunc: func(id int) (*models.Question, error) {
+					return &models.Question{ID: id, DeletedBy: &deleter}, nil
+				},
+				restoreFunc: func(id int) error {
+					restored = true
+					return nil
+				},
+			}
+			s := NewQuestionService(questions, &mockMetrics{})
+
+			err := s.Restore(context.Background(), 1, tt.userID, tt.admin)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			if restored != (tt.expectedErr == nil) {
+				t.Errorf("expected restored %v, got %v", tt.expectedErr == nil, restored)
+			}
+		})
+	}
+}
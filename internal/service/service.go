@@ -0,0 +1,8 @@
+// Package service holds the rules of questions and answers, independent of the API that exposes them.
+package service
+
+// Metrics counts what the services do, e.g. for Prometheus.
+type Metrics interface {
+	QuestionCreated()
+	AnswerCreated()
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	maxTagsPerQuestion = 5
+	maxTagsPerFilter   = 20 // Questions having any or all of them, it is not tied to maxTagsPerQuestion
+	maxTagLength       = 35
+)
+
+// Letters, digits and a few symbols used in technology names, e.g. "c++", "c#", "asp.net"
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#.]*(-[a-z0-9+#.]+)*$`)
+
+// normalizeTag lowercases the name and turns spaces and underscores into single dashes.
+func normalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), "-")
+
+	if name == "" {
+		return "", errors.New("tag cannot be empty")
+	}
+
+	if len(name) > maxTagLength || !tagPattern.MatchString(name) {
+		return "", errors.New("invalid tag: " + name)
+	}
+
+	return name, nil
+}
+
+// normalizeTags also drops duplicates that only differ in case or separators.
+func normalizeTags(names []string) ([]string, error) {
+	normalized, err := uniqueTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(normalized) > maxTagsPerQuestion {
+		return nil, fmt.Errorf("a question can have at most %d tags", maxTagsPerQuestion)
+	}
+
+	return normalized, nil
+}
+
+// normalizeTagFilter normalizes the tags questions are filtered by like normalizeTags, with a limit of its own.
+func normalizeTagFilter(names []string) ([]string, error) {
+	normalized, err := uniqueTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(normalized) > maxTagsPerFilter {
+		return nil, fmt.Errorf("at most %d tags can be filtered by", maxTagsPerFilter)
+	}
+
+	return normalized, nil
+}
+
+func uniqueTags(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+
+	for _, name := range names {
+		tag, err := normalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized, nil
+}
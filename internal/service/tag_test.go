@@ -0,0 +1,46 @@
+package service
+
+import "testing"
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "Go", expected: "go"},
+		{input: "  PostgreSQL ", expected: "postgresql"},
+		{input: "Unit Testing", expected: "unit-testing"},
+		{input: "http_client", expected: "http-client"},
+		{input: "c++", expected: "c++"},
+		{input: "C#", expected: "c#"},
+		{input: "asp.net", expected: "asp.net"},
+		{input: "a -- b", expected: "a-b"},
+		{input: "", wantErr: true},
+		{input: "   ", wantErr: true},
+		{input: "<script>", wantErr: true},
+		{input: "++", wantErr: true},
+		{input: "this-tag-is-way-too-long-to-be-accepted", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := normalizeTag(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
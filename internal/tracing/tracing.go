@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+const (
+	ExporterOTLP   = "otlp"   // OTLP over HTTP, endpoint taken from OTEL_EXPORTER_OTLP_ENDPOINT
+	ExporterStdout = "stdout" // One JSON object per span, for local debugging
+	ExporterNone   = "none"
+)
+
+// NewProvider creates a provider sending spans to the exporter. With ExporterNone spans are not recorded,
+// but they still get IDs, so that log lines of a request can be correlated.
+// The provider must be shut down to flush the remaining spans.
+func NewProvider(ctx context.Context, exporter, serviceName string, sampleRatio float64) (*sdktrace.TracerProvider, error) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	}
+
+	switch exporter {
+	case ExporterOTLP:
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case ExporterStdout:
+		exp, err := stdouttrace.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case ExporterNone:
+		opts = append(opts, sdktrace.WithSampler(sdktrace.NeverSample()))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter: %q", exporter)
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		exporter  string
+		recording bool
+		wantErr   bool
+	}{
+		{name: "otlp", exporter: ExporterOTLP, recording: true},
+		{name: "stdout", exporter: ExporterStdout, recording: true},
+		{name: "none", exporter: ExporterNone, recording: false},
+		{name: "unknown", exporter: "jaeger", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, err := NewProvider(context.Background(), tt.exporter, "go-qa-service", 1)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, span := tp.Tracer("test").Start(context.Background(), "span")
+			if span.IsRecording() != tt.recording {
+				t.Errorf("expected recording %v, got %v", tt.recording, span.IsRecording())
+			}
+			if !span.SpanContext().IsValid() {
+				t.Error("expected the span to have IDs")
+			}
+
+			// The span is not ended, so there is nothing to export
+			if err := tp.Shutdown(context.Background()); err != nil {
+				t.Errorf("failed to shut down: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Package trash removes deleted questions and answers for good once they have been in the trash
+// longer than the retention period.
+package trash
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Store is implemented by repository.TrashRepository.
+type Store interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, int64, error)
+}
+
+// Purger runs the purge every interval. Replicas may purge at the same time, the purge is idempotent.
+type Purger struct {
+	store     Store
+	retention time.Duration
+	interval  time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewPurger(store Store, retention, interval time.Duration, logger *slog.Logger) *Purger {
+	return &Purger{store: store, retention: retention, interval: interval, logger: logger, now: time.Now}
+}
+
+// Run purges right away and then every interval until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes what was deleted more than the retention period ago. Failures are only logged,
+// the next run tries again.
+func (p *Purger) Purge(ctx context.Context) {
+	before := p.now().Add(-p.retention)
+
+	questions, answers, err := p.store.PurgeDeleted(ctx, before)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.ErrorContext(ctx, "failed to purge trash", "error", err)
+		}
+
+		return
+	}
+
+	if questions > 0 || answers > 0 {
+		p.logger.InfoContext(ctx, "purged trash", "questions", questions, "answers", answers, "deleted_before", before)
+	}
+}
@@ -0,0 +1,69 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+type fakeStore struct {
+	calls  []time.Time
+	err    error
+	onCall func()
+}
+
+func (s *fakeStore) PurgeDeleted(ctx context.Context, before time.Time) (int64, int64, error) {
+	s.calls = append(s.calls, before)
+	if s.onCall != nil {
+		s.onCall()
+	}
+
+	return 1, 2, s.err
+}
+
+func TestPurge(t *testing.T) {
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{}
+
+	purger := NewPurger(store, 30*24*time.Hour, time.Hour, pkg.NewLogger("error", "json"))
+	purger.now = func() time.Time { return now }
+
+	purger.Purge(context.Background())
+
+	expected := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	if len(store.calls) != 1 || !store.calls[0].Equal(expected) {
+		t.Errorf("expected a purge of what was deleted before %v, got %v", expected, store.calls)
+	}
+
+	// A failed purge is retried on the next run
+	store.err = errors.New("connection refused")
+	purger.Purge(context.Background())
+
+	if len(store.calls) != 2 {
+		t.Errorf("expected 2 purges, got %d", len(store.calls))
+	}
+}
+
+func TestRunPurgesRightAwayUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := &fakeStore{onCall: cancel}
+
+	done := make(chan struct{})
+	go func() {
+		NewPurger(store, time.Hour, time.Hour, pkg.NewLogger("error", "json")).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return once the context is cancelled")
+	}
+
+	if len(store.calls) != 1 {
+		t.Errorf("expected one purge before the first tick, got %d", len(store.calls))
+	}
+}
@@ -0,0 +1,226 @@
+// Package webhook sends the deliveries queued for webhook subscriptions and retries the failed ones
+// with exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+)
+
+// Events are the audit actions a subscription can be notified of.
+var Events = []string{
+	audit.QuestionCreated,
+	audit.QuestionUpdated,
+	audit.QuestionDeleted,
+	audit.QuestionRestored,
+	audit.QuestionVoted,
+	audit.QuestionAccepted,
+	audit.QuestionUnaccepted,
+	audit.AnswerCreated,
+	audit.AnswerUpdated,
+	audit.AnswerDeleted,
+	audit.AnswerRestored,
+	audit.AnswerVoted,
+}
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery" // The same for every attempt of a delivery
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// A full batch means more deliveries may be due, the next one is claimed right away
+const batchSize = 20
+
+// Only the start of the response is read, for the connection to be reused
+const maxResponseDrain = 64 << 10
+
+// NewSecret generates the key signing the deliveries of a subscription.
+func NewSecret() string {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key) // Never fails
+
+	return hex.EncodeToString(key)
+}
+
+// Sign returns the signature of a delivery: "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>".
+// Receivers compute it the same way and reject old timestamps, so that a captured delivery can't be replayed.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Store is implemented by repository.WebhookRepository.
+type Store interface {
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	FinishDelivery(ctx context.Context, id int, result repository.DeliveryResult) error
+}
+
+// Backoff waits Min before the first retry and twice as long before every next one, up to Max.
+// A delivery is given up after MaxAttempts.
+type Backoff struct {
+	Min         time.Duration
+	Max         time.Duration
+	MaxAttempts int
+}
+
+// Delay is the wait after the given number of failed attempts.
+func (b Backoff) Delay(failed int) time.Duration {
+	delay := b.Min
+	for i := 1; i < failed && delay < b.Max; i++ {
+		delay *= 2
+	}
+
+	return min(delay, b.Max)
+}
+
+// Dispatcher sends the due deliveries every interval. Replicas may dispatch at the same time,
+// each delivery is claimed by one of them. Deliveries are sent at least once, receivers should
+// deduplicate them by the event ID of the payload.
+type Dispatcher struct {
+	store    Store
+	client   *http.Client
+	interval time.Duration
+	lease    time.Duration
+	backoff  Backoff
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewDispatcher gives up on a request after timeout. A claimed delivery is left to other replicas for twice as long,
+// in case this one dies before it is finished.
+func NewDispatcher(store Store, interval, timeout time.Duration, backoff Backoff, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:    store,
+		client:   &http.Client{Timeout: timeout},
+		interval: interval,
+		lease:    2 * timeout,
+		backoff:  backoff,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Run dispatches right away and then every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if d.Dispatch(ctx) == batchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch sends a batch of due deliveries at once and returns how many it claimed.
+// Failures are only logged, failed deliveries are retried by a later run.
+func (d *Dispatcher) Dispatch(ctx context.Context) int {
+	deliveries, err := d.store.ClaimDeliveries(ctx, batchSize, d.lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.logger.ErrorContext(ctx, "failed to claim webhook deliveries", "error", err)
+		}
+
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Go(func() {
+			d.deliver(ctx, &deliveries[i])
+		})
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	result := d.send(ctx, delivery)
+	// Interrupted by shutdown, the delivery is sent again once its lease is over
+	if ctx.Err() != nil {
+		return
+	}
+
+	log := d.logger.With("delivery_id", delivery.ID, "subscription_id", delivery.SubscriptionID, "event", delivery.Event)
+
+	if !result.Delivered {
+		failed := delivery.Attempts + 1
+		if failed < d.backoff.MaxAttempts {
+			next := d.now().Add(d.backoff.Delay(failed))
+			result.NextAttemptAt = &next
+			log.WarnContext(ctx, "webhook delivery failed, will retry", "error", result.Error, "attempts", failed, "next_attempt_at", next)
+		} else {
+			log.ErrorContext(ctx, "webhook delivery failed, giving up", "error", result.Error, "attempts", failed)
+		}
+	}
+
+	// Not found when the subscription was deleted meanwhile, there is nothing left to update
+	if err := d.store.FinishDelivery(ctx, delivery.ID, result); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.ErrorContext(ctx, "failed to save webhook delivery", "error", err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *models.WebhookDelivery) repository.DeliveryResult {
+	subscription := delivery.Subscription
+	if subscription == nil {
+		return repository.DeliveryResult{Error: "subscription not found"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return repository.DeliveryResult{Error: err.Error()}
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-qa-service-webhooks")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(delivery.ID))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return repository.DeliveryResult{Error: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	result := repository.DeliveryResult{StatusCode: &resp.StatusCode}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		result.Delivered = true
+	} else {
+		result.Error = "unexpected status " + resp.Status
+	}
+
+	return result
+}
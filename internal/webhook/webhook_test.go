@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/repository"
+	"github.com/makson2134/go-qa-service/pkg"
+)
+
+type fakeStore struct {
+	mu         sync.Mutex
+	deliveries []models.WebhookDelivery
+	leases     []time.Duration
+	results    map[int]repository.DeliveryResult
+}
+
+func (s *fakeStore) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	s.leases = append(s.leases, lease)
+	claimed := s.deliveries[:min(limit, len(s.deliveries))]
+	s.deliveries = s.deliveries[len(claimed):]
+
+	return claimed, nil
+}
+
+func (s *fakeStore) FinishDelivery(ctx context.Context, id int, result repository.DeliveryResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.results == nil {
+		s.results = make(map[int]repository.DeliveryResult)
+	}
+	s.results[id] = result
+
+	return nil
+}
+
+func newDelivery(id int, url string, attempts int) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:             id,
+		SubscriptionID: 1,
+		Event:          "question.created",
+		Payload:        json.RawMessage(`{"id":42,"event":"question.created"}`),
+		Attempts:       attempts,
+		Subscription:   &models.WebhookSubscription{ID: 1, URL: url, Secret: "secret"},
+	}
+}
+
+func TestDispatchDeliversSignedPayload(t *testing.T) {
+	now := time.Unix(1767225600, 0)
+
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := &fakeStore{deliveries: []models.WebhookDelivery{newDelivery(7, server.URL, 0)}}
+	dispatcher := NewDispatcher(store, time.Minute, 10*time.Second, Backoff{Min: time.Minute, Max: time.Hour, MaxAttempts: 5}, pkg.NewLogger("error", "json"))
+	dispatcher.now = func() time.Time { return now }
+
+	if claimed := dispatcher.Dispatch(context.Background()); claimed != 1 {
+		t.Fatalf("expected 1 delivery claimed, got %d", claimed)
+	}
+
+	if got == nil {
+		t.Fatal("expected the payload to be posted")
+	}
+	if string(body) != `{"id":42,"event":"question.created"}` {
+		t.Errorf("expected the payload as queued, got %s", body)
+	}
+	if got.Header.Get(HeaderEvent) != "question.created" || got.Header.Get(HeaderDelivery) != "7" {
+		t.Errorf("expected event and delivery headers, got %v", got.Header)
+	}
+
+	timestamp, _ := strconv.ParseInt(got.Header.Get(HeaderTimestamp), 10, 64)
+	if timestamp != now.Unix() || got.Header.Get(HeaderSignature) != Sign("secret", timestamp, body) {
+		t.Errorf("expected the payload signed with the secret at %d, got %v", now.Unix(), got.Header)
+	}
+
+	result := store.results[7]
+	if !result.Delivered || result.StatusCode == nil || *result.StatusCode != http.StatusNoContent {
+		t.Errorf("expected delivered with 204, got %+v", result)
+	}
+	if len(store.leases) != 1 || store.leases[0] != 20*time.Second {
+		t.Errorf("expected deliveries leased for twice the timeout, got %v", store.leases)
+	}
+}
+
+func TestDispatchRetriesFailures(t *testing.T) {
+	now := time.Unix(1767225600, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	store := &fakeStore{deliveries: []models.WebhookDelivery{
+		newDelivery(1, server.URL, 0),
+		newDelivery(2, server.URL, 2),
+		newDelivery(3, server.URL, 4), // The last attempt
+		newDelivery(4, "http://127.0.0.1:1", 0),
+	}}
+	dispatcher := NewDispatcher(store, time.Minute, time.Second, Backoff{Min: time.Minute, Max: time.Hour, MaxAttempts: 5}, pkg.NewLogger("error", "json"))
+	dispatcher.now = func() time.Time { return now }
+
+	dispatcher.Dispatch(context.Background())
+
+	tests := []struct {
+		id            int
+		expectedRetry time.Duration // 0 when given up
+		expectedCode  int
+	}{
+		{id: 1, expectedRetry: time.Minute, expectedCode: http.StatusServiceUnavailable},
+		{id: 2, expectedRetry: 4 * time.Minute, expectedCode: http.StatusServiceUnavailable},
+		{id: 3, expectedCode: http.StatusServiceUnavailable},
+		{id: 4, expectedRetry: time.Minute},
+	}
+
+	for _, tt := range tests {
+		result := store.results[tt.id]
+
+		if result.Delivered || result.Error == "" {
+			t.Errorf("delivery %d: expected a failure with its error, got %+v", tt.id, result)
+		}
+
+		if tt.expectedCode == 0 && result.StatusCode != nil || tt.expectedCode != 0 && (result.StatusCode == nil || *result.StatusCode != tt.expectedCode) {
+			t.Errorf("delivery %d: expected status %d, got %v", tt.id, tt.expectedCode, result.StatusCode)
+		}
+
+		if tt.expectedRetry == 0 {
+			if result.NextAttemptAt != nil {
+				t.Errorf("delivery %d: expected no retry, got %v", tt.id, result.NextAttemptAt)
+			}
+		} else if result.NextAttemptAt == nil || !result.NextAttemptAt.Equal(now.Add(tt.expectedRetry)) {
+			t.Errorf("delivery %d: expected retry in %v, got %v", tt.id, tt.expectedRetry, result.NextAttemptAt)
+		}
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := Backoff{Min: 30 * time.Second, Max: 10 * time.Minute}
+
+	tests := []struct {
+		failed   int
+		expected time.Duration
+	}{
+		{failed: 1, expected: 30 * time.Second},
+		{failed: 2, expected: time.Minute},
+		{failed: 5, expected: 8 * time.Minute},
+		{failed: 6, expected: 10 * time.Minute},
+		{failed: 100, expected: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := backoff.Delay(tt.failed); got != tt.expected {
+			t.Errorf("after %d failures: expected %v, got %v", tt.failed, tt.expected, got)
+		}
+	}
+}
+
+func TestRunDispatchesRightAwayUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+	}))
+	defer server.Close()
+
+	store := &fakeStore{deliveries: []models.WebhookDelivery{newDelivery(1, server.URL, 0)}}
+
+	done := make(chan struct{})
+	go func() {
+		NewDispatcher(store, time.Hour, time.Second, Backoff{Min: time.Minute, Max: time.Hour, MaxAttempts: 5}, pkg.NewLogger("error", "json")).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to return once the context is cancelled")
+	}
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE questions
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', text)) STORED;
+
+ALTER TABLE answers
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', text)) STORED;
+
+CREATE INDEX idx_questions_search_vector ON questions USING GIN (search_vector);
+CREATE INDEX idx_answers_search_vector ON answers USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_answers_search_vector;
+DROP INDEX IF EXISTS idx_questions_search_vector;
+
+ALTER TABLE answers DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE questions DROP COLUMN IF EXISTS search_vector;
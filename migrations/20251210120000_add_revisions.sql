@@ -0,0 +1,35 @@
+-- +goose Up
+ALTER TABLE questions ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE answers ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE questions SET updated_at = created_at;
+UPDATE answers SET updated_at = created_at;
+
+-- created_at is when the revision text was written, replaced_at is when it was edited away
+CREATE TABLE question_revisions (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_question_revision_question FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE answer_revisions (
+    id SERIAL PRIMARY KEY,
+    answer_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_answer_revision_answer FOREIGN KEY (answer_id) REFERENCES answers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_question_revisions_question_id ON question_revisions(question_id);
+CREATE INDEX idx_answer_revisions_answer_id ON answer_revisions(answer_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS answer_revisions;
+DROP TABLE IF EXISTS question_revisions;
+
+ALTER TABLE answers DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE questions DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE users (
+    id VARCHAR(255) PRIMARY KEY,
+    display_name VARCHAR(100) NOT NULL,
+    bio TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Every user_id that has ever answered becomes a user, named after the ID
+INSERT INTO users (id, display_name, created_at)
+SELECT user_id, LEFT(user_id, 100), MIN(created_at)
+FROM answers
+GROUP BY user_id;
+
+ALTER TABLE answers
+    ADD CONSTRAINT fk_answer_user FOREIGN KEY (user_id) REFERENCES users(id);
+
+-- Questions asked before users existed have no author
+ALTER TABLE questions ADD COLUMN author_id VARCHAR(255);
+ALTER TABLE questions
+    ADD CONSTRAINT fk_question_author FOREIGN KEY (author_id) REFERENCES users(id);
+
+CREATE INDEX idx_answers_user_id ON answers(user_id);
+CREATE INDEX idx_questions_author_id ON questions(author_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_questions_author_id;
+DROP INDEX IF EXISTS idx_answers_user_id;
+
+ALTER TABLE questions DROP COLUMN IF EXISTS author_id;
+ALTER TABLE answers DROP CONSTRAINT IF EXISTS fk_answer_user;
+
+DROP TABLE IF EXISTS users;
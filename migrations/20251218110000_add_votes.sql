@@ -0,0 +1,38 @@
+-- +goose Up
+ALTER TABLE questions ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE answers ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE question_votes (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    value SMALLINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_question_votes_question_user UNIQUE (question_id, user_id),
+    CONSTRAINT chk_question_votes_value CHECK (value IN (-1, 1)),
+    CONSTRAINT fk_question_vote_question FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_question_vote_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE answer_votes (
+    id SERIAL PRIMARY KEY,
+    answer_id INTEGER NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    value SMALLINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_answer_votes_answer_user UNIQUE (answer_id, user_id),
+    CONSTRAINT chk_answer_votes_value CHECK (value IN (-1, 1)),
+    CONSTRAINT fk_answer_vote_answer FOREIGN KEY (answer_id) REFERENCES answers(id) ON DELETE CASCADE,
+    CONSTRAINT fk_answer_vote_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_questions_score ON questions(score DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_questions_score;
+
+DROP TABLE IF EXISTS answer_votes;
+DROP TABLE IF EXISTS question_votes;
+
+ALTER TABLE answers DROP COLUMN IF EXISTS score;
+ALTER TABLE questions DROP COLUMN IF EXISTS score;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE questions ADD COLUMN accepted_answer_id INTEGER;
+ALTER TABLE questions
+    ADD CONSTRAINT fk_question_accepted_answer FOREIGN KEY (accepted_answer_id) REFERENCES answers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_questions_accepted_answer_id ON questions(accepted_answer_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_questions_accepted_answer_id;
+ALTER TABLE questions DROP COLUMN IF EXISTS accepted_answer_id;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(35) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_tags_name UNIQUE (name)
+);
+
+CREATE TABLE question_tags (
+    question_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (question_id, tag_id),
+    CONSTRAINT fk_question_tag_question FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_question_tag_tag FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_question_tags_tag_id ON question_tags(tag_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS question_tags;
+DROP TABLE IF EXISTS tags;
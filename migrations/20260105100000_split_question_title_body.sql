@@ -0,0 +1,40 @@
+-- +goose Up
+-- The first line of the old text becomes the title, the whole text is kept as the body
+ALTER TABLE questions ADD COLUMN title VARCHAR(150);
+UPDATE questions SET title = left(btrim(split_part(btrim(text, E' \t\r\n'), E'\n', 1), E' \t\r'), 150);
+ALTER TABLE questions ALTER COLUMN title SET NOT NULL;
+ALTER TABLE questions RENAME COLUMN text TO body;
+
+ALTER TABLE question_revisions ADD COLUMN title VARCHAR(150);
+UPDATE question_revisions SET title = left(btrim(split_part(btrim(text, E' \t\r\n'), E'\n', 1), E' \t\r'), 150);
+ALTER TABLE question_revisions ALTER COLUMN title SET NOT NULL;
+ALTER TABLE question_revisions RENAME COLUMN text TO body;
+
+ALTER TABLE answers RENAME COLUMN text TO body;
+ALTER TABLE answer_revisions RENAME COLUMN text TO body;
+
+-- Generated columns can't be altered, the title is ranked above the body
+DROP INDEX IF EXISTS idx_questions_search_vector;
+ALTER TABLE questions DROP COLUMN search_vector;
+ALTER TABLE questions
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') || setweight(to_tsvector('english', body), 'B')
+    ) STORED;
+CREATE INDEX idx_questions_search_vector ON questions USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_questions_search_vector;
+ALTER TABLE questions DROP COLUMN search_vector;
+
+ALTER TABLE answer_revisions RENAME COLUMN body TO text;
+ALTER TABLE answers RENAME COLUMN body TO text;
+
+ALTER TABLE question_revisions RENAME COLUMN body TO text;
+ALTER TABLE question_revisions DROP COLUMN title;
+
+ALTER TABLE questions RENAME COLUMN body TO text;
+ALTER TABLE questions DROP COLUMN title;
+
+ALTER TABLE questions
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', text)) STORED;
+CREATE INDEX idx_questions_search_vector ON questions USING GIN (search_vector);
@@ -0,0 +1,23 @@
+-- +goose Up
+-- A comment is attached to exactly one question or answer, replies share the target of their parent
+CREATE TABLE comments (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER,
+    answer_id INTEGER,
+    parent_id INTEGER,
+    user_id VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_comments_target CHECK ((question_id IS NULL) <> (answer_id IS NULL)),
+    CONSTRAINT fk_comment_question FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_answer FOREIGN KEY (answer_id) REFERENCES answers(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_parent FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_comments_question_id ON comments(question_id);
+CREATE INDEX idx_comments_answer_id ON comments(answer_id);
+CREATE INDEX idx_comments_parent_id ON comments(parent_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS comments;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Token buckets shared by all replicas. Losing them on a crash only resets the limits, so no WAL is written
+CREATE UNLOGGED TABLE rate_limits (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL, -- Whether the last request took a token
+    updated_at TIMESTAMPTZ NOT NULL,
+    full_at TIMESTAMPTZ NOT NULL -- A full bucket is the same as a missing one and can be deleted
+);
+
+CREATE INDEX idx_rate_limits_full_at ON rate_limits(full_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS rate_limits;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- Deleted questions and answers stay in the trash until the purge job removes them for good.
+-- Answers of a deleted question keep deleted_at NULL, they are hidden through their question
+ALTER TABLE questions ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE questions ADD COLUMN deleted_by VARCHAR(255);
+ALTER TABLE answers ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE answers ADD COLUMN deleted_by VARCHAR(255);
+
+-- Only the trash listing and the purge look for deleted rows
+CREATE INDEX idx_questions_deleted_at ON questions(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_answers_deleted_at ON answers(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+-- Questions and answers still in the trash become visible again
+DROP INDEX IF EXISTS idx_answers_deleted_at;
+DROP INDEX IF EXISTS idx_questions_deleted_at;
+
+ALTER TABLE answers DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE answers DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE questions DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE questions DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- Every change made through the API, written in the same transaction as the change itself.
+-- Before and after are JSON snapshots of the entity, NULL when it didn't exist yet or is gone
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255), -- NULL for anonymous requests and changes made outside of a request
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL, -- Users have string IDs
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(128),
+    client_ip VARCHAR(45),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Events are listed newest first, by entity or by actor
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id, id);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor, id);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+-- The log is append-only, even for the application's own database user
+-- +goose StatementBegin
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
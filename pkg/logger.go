@@ -1,12 +1,16 @@
 package pkg
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"strings"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Warning level and json format by default
+// Warning level and json format by default. Records logged with a context (InfoContext, ErrorContext, ...)
+// get trace_id and span_id of the span in it.
 func NewLogger(level, format string) *slog.Logger {
 	var logLevel slog.Level
 	switch strings.ToLower(level) {
@@ -33,5 +37,40 @@ func NewLogger(level, format string) *slog.Logger {
 		})
 	}
 
-	return slog.New(handler)
+	return slog.New(traceHandler{handler})
+}
+
+type loggerKey struct{}
+
+// WithLogger puts a logger with the attributes of the current request into the context.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+func LoggerFromContext(ctx context.Context) (*slog.Logger, bool) {
+	l, ok := ctx.Value(loggerKey{}).(*slog.Logger)
+	return l, ok
+}
+
+type traceHandler struct {
+	slog.Handler
+}
+
+func (h traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{h.Handler.WithGroup(name)}
 }
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHandler(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	spanCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		expectedTraceID string
+		expectedSpanID  string
+	}{
+		{name: "span in context", ctx: spanCtx, expectedTraceID: traceID.String(), expectedSpanID: spanID.String()},
+		{name: "no span", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(traceHandler{slog.NewJSONHandler(&buf, nil)}).With("component", "test")
+
+			logger.InfoContext(tt.ctx, "hello")
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to decode log record: %v", err)
+			}
+
+			traceIDValue, _ := record["trace_id"].(string)
+			spanIDValue, _ := record["span_id"].(string)
+			if traceIDValue != tt.expectedTraceID || spanIDValue != tt.expectedSpanID {
+				t.Errorf("expected trace_id %q and span_id %q, got %q and %q", tt.expectedTraceID, tt.expectedSpanID, traceIDValue, spanIDValue)
+			}
+			if record["component"] != "test" {
+				t.Error("expected attributes of With to be kept")
+			}
+		})
+	}
+}
@@ -2,15 +2,40 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/makson2134/go-qa-service/internal/audit"
+	"github.com/makson2134/go-qa-service/internal/metrics"
+	"github.com/makson2134/go-qa-service/internal/models"
+	"github.com/makson2134/go-qa-service/internal/ratelimit"
+	"github.com/makson2134/go-qa-service/internal/repository"
 	"github.com/makson2134/go-qa-service/internal/repository/postgres"
+	"github.com/makson2134/go-qa-service/internal/service"
+	"github.com/makson2134/go-qa-service/internal/webhook"
 	"github.com/pressly/goose/v3"
 	testcontainerspostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func setupTestDB(t *testing.T) (*postgres.DB, func()) {
+	return setupTracedTestDB(t, noop.NewTracerProvider())
+}
+
+func setupTracedTestDB(t *testing.T, tp trace.TracerProvider) (*postgres.DB, func()) {
 	ctx := context.Background()
 
 	postgresContainer, err := testcontainerspostgres.Run(ctx,
@@ -30,7 +55,7 @@ func setupTestDB(t *testing.T) (*postgres.DB, func()) {
 		t.Fatalf("failed to get connection string: %v", err)
 	}
 
-	db, err := postgres.New(connStr, 10, 5, time.Hour)
+	db, err := postgres.New(connStr, 10, 5, time.Hour, tp)
 	if err != nil {
 		t.Fatalf("failed to connect to database: %v", err)
 	}
@@ -48,6 +73,13 @@ func setupTestDB(t *testing.T) (*postgres.DB, func()) {
 		t.Fatalf("failed to run migrations: %v", err)
 	}
 
+	// Questions and answers need registered authors
+	for _, userID := range []string{"user1", "user2"} {
+		if _, err := db.CreateUser(ctx, userID, userID, ""); err != nil {
+			t.Fatalf("failed to create user %s: %v", userID, err)
+		}
+	}
+
 	cleanup := func() {
 		if err := db.Close(); err != nil {
 			t.Logf("failed to close database connection: %v", err)
@@ -64,69 +96,1376 @@ func TestMultipleAnswersFromSameUser(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	question, err := db.Create("What is Go?")
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
 	if err != nil {
 		t.Fatalf("failed to create question: %v", err)
 	}
 
-	userID := "user123"
+	userID := "user1"
 
-	_, err = db.CreateAnswer(question.ID, userID, "Go is a programming language")
+	_, err = db.CreateAnswer(ctx, question.ID, userID, "Go is a programming language")
 	if err != nil {
 		t.Fatalf("failed to create first answer: %v", err)
 	}
 
-	_, err = db.CreateAnswer(question.ID, userID, "Go was created by Google")
+	_, err = db.CreateAnswer(ctx, question.ID, userID, "Go was created by Google")
 	if err != nil {
 		t.Fatalf("failed to create second answer: %v", err)
 	}
 
-	fetchedQuestion, err := db.GetByID(question.ID)
+	fetchedQuestion, err := db.GetByID(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to get question: %v", err)
+	}
+
+	if fetchedQuestion.AnswersCount != 2 {
+		t.Errorf("expected answers_count 2, got %d", fetchedQuestion.AnswersCount)
+	}
+
+	answers, _, err := db.ListAnswers(ctx, question.ID, repository.ListAnswersOptions{Limit: 10, Sort: repository.AnswerSortOldest})
 	if err != nil {
-		t.Fatalf("failed to get question with answers: %v", err)
+		t.Fatalf("failed to list answers: %v", err)
 	}
 
-	if len(fetchedQuestion.Answers) != 2 {
-		t.Fatalf("expected 2 answers, got %d", len(fetchedQuestion.Answers))
+	if len(answers) != 2 {
+		t.Fatalf("expected 2 answers, got %d", len(answers))
 	}
 
-	for _, answer := range fetchedQuestion.Answers {
+	for _, answer := range answers {
 		if answer.UserID != userID {
 			t.Errorf("expected user_id %s, got %s", userID, answer.UserID)
 		}
 	}
 }
 
-func TestCascadeDeleteQuestionDeletesAnswers(t *testing.T) {
+func TestDeleteQuestionHidesAnswersUntilRestored(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	question, err := db.Create("What is Docker?")
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Docker?", "Details about containers", []string{"docker"})
 	if err != nil {
 		t.Fatalf("failed to create question: %v", err)
 	}
 
-	answer1, err := db.CreateAnswer(question.ID, "user1", "Docker is a containerization platform")
+	answer1, err := db.CreateAnswer(ctx, question.ID, "user1", "Docker is a containerization platform")
 	if err != nil {
 		t.Fatalf("failed to create first answer: %v", err)
 	}
 
-	answer2, err := db.CreateAnswer(question.ID, "user2", "Docker uses containers")
+	answer2, err := db.CreateAnswer(ctx, question.ID, "user2", "Docker uses containers")
 	if err != nil {
 		t.Fatalf("failed to create second answer: %v", err)
 	}
 
-	if err := db.Delete(question.ID); err != nil {
+	if err := db.Delete(ctx, question.ID, "user2", false); !errors.Is(err, repository.ErrNotOwner) {
+		t.Fatalf("expected repository.ErrNotOwner for another user, got %v", err)
+	}
+
+	if err := db.Delete(ctx, question.ID, "moderator", true); err != nil {
+		t.Fatalf("failed to delete question: %v", err)
+	}
+
+	if _, err := db.GetByID(ctx, question.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected deleted question to be not found, got %v", err)
+	}
+
+	for _, id := range []int{answer1.ID, answer2.ID} {
+		if _, err := db.GetAnswerByID(ctx, id); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("expected answer %d of the deleted question to be not found, got %v", id, err)
+		}
+	}
+
+	questions, _, err := db.List(ctx, repository.ListQuestionsOptions{Limit: 10})
+	if err != nil || len(questions) != 0 {
+		t.Errorf("expected no questions in the list, got %d, %v", len(questions), err)
+	}
+
+	hits, err := db.Search(ctx, "docker", 10)
+	if err != nil || len(hits) != 0 {
+		t.Errorf("expected no search hits, got %+v, %v", hits, err)
+	}
+
+	tags, err := db.ListTags(ctx, 10)
+	if err != nil || len(tags) != 0 {
+		t.Errorf("expected no tags in use, got %+v, %v", tags, err)
+	}
+
+	if err := db.Delete(ctx, question.ID, "moderator", true); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected deleting twice to be not found, got %v", err)
+	}
+
+	deleted, err := db.GetDeleted(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to get deleted question: %v", err)
+	}
+
+	if deleted.DeletedBy == nil || *deleted.DeletedBy != "user2" || !deleted.DeletedAt.Valid {
+		t.Errorf("expected question deleted by user2, got %v at %v", deleted.DeletedBy, deleted.DeletedAt)
+	}
+
+	if err := db.Restore(ctx, question.ID); err != nil {
+		t.Fatalf("failed to restore question: %v", err)
+	}
+
+	restored, err := db.GetByID(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to get restored question: %v", err)
+	}
+
+	if restored.AnswersCount != 2 || restored.DeletedBy != nil {
+		t.Errorf("expected restored question with 2 answers, got %d answers, deleted by %v", restored.AnswersCount, restored.DeletedBy)
+	}
+
+	if err := db.Restore(ctx, question.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected restoring a question outside the trash to be not found, got %v", err)
+	}
+}
+
+func TestDeleteAndRestoreAnswer(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answer, err := db.CreateAnswer(ctx, question.ID, "user2", "A language")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	if err := db.DeleteAnswer(ctx, answer.ID, "user2", false); err != nil {
+		t.Fatalf("failed to delete answer: %v", err)
+	}
+
+	if _, err := db.GetAnswerByID(ctx, answer.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected deleted answer to be not found, got %v", err)
+	}
+
+	answers, _, err := db.ListAnswers(ctx, question.ID, repository.ListAnswersOptions{Limit: 10})
+	if err != nil || len(answers) != 0 {
+		t.Errorf("expected no answers listed, got %d, %v", len(answers), err)
+	}
+
+	byUser, _, err := db.ListAnswersByUser(ctx, "user2", 10, nil)
+	if err != nil || len(byUser) != 0 {
+		t.Errorf("expected no answers of user2, got %d, %v", len(byUser), err)
+	}
+
+	fetched, err := db.GetByID(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to get question: %v", err)
+	}
+
+	if fetched.AnswersCount != 0 {
+		t.Errorf("expected deleted answer not to be counted, got %d", fetched.AnswersCount)
+	}
+
+	if _, err := db.VoteAnswer(ctx, answer.ID, "user1", 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected vote on deleted answer to be not found, got %v", err)
+	}
+
+	trashed, _, err := db.ListDeletedAnswers(ctx, 10, nil)
+	if err != nil || len(trashed) != 1 || trashed[0].ID != answer.ID {
+		t.Fatalf("expected the answer in the trash, got %+v, %v", trashed, err)
+	}
+
+	// The answer goes back to the trash of its question
+	if err := db.Delete(ctx, question.ID, "user1", false); err != nil {
+		t.Fatalf("failed to delete question: %v", err)
+	}
+
+	trashed, _, err = db.ListDeletedAnswers(ctx, 10, nil)
+	if err != nil || len(trashed) != 0 {
+		t.Errorf("expected no answers of the deleted question in the trash, got %+v, %v", trashed, err)
+	}
+
+	if _, err := db.GetDeletedAnswer(ctx, answer.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected the answer of the deleted question to be not found, got %v", err)
+	}
+
+	if err := db.RestoreAnswer(ctx, answer.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected restoring the answer of a deleted question to be not found, got %v", err)
+	}
+
+	if err := db.Restore(ctx, question.ID); err != nil {
+		t.Fatalf("failed to restore question: %v", err)
+	}
+
+	if err := db.RestoreAnswer(ctx, answer.ID); err != nil {
+		t.Fatalf("failed to restore answer: %v", err)
+	}
+
+	if _, err := db.GetAnswerByID(ctx, answer.ID); err != nil {
+		t.Errorf("expected restored answer, got %v", err)
+	}
+}
+
+func TestListDeletedQuestionsKeysetPagination(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	var ids []int
+	for _, title := range []string{"First?", "Second?", "Third?", "Fourth?", "Fifth?"} {
+		question, err := db.Create(ctx, "user1", title, "Details", nil)
+		if err != nil {
+			t.Fatalf("failed to create question: %v", err)
+		}
+
+		if err := db.Delete(ctx, question.ID, "user1", false); err != nil {
+			t.Fatalf("failed to delete question: %v", err)
+		}
+
+		ids = append(ids, question.ID)
+	}
+
+	// The most recently deleted first
+	slices.Reverse(ids)
+
+	var got []int
+	var cursor *repository.Cursor
+	for {
+		page, next, err := db.ListDeletedQuestions(ctx, 2, cursor)
+		if err != nil {
+			t.Fatalf("failed to list deleted questions: %v", err)
+		}
+
+		for _, q := range page {
+			got = append(got, q.ID)
+		}
+
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+
+	if !slices.Equal(got, ids) {
+		t.Errorf("expected %v, got %v", ids, got)
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answer, err := db.CreateAnswer(ctx, question.ID, "user2", "A language")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	kept, err := db.Create(ctx, "user1", "What is Docker?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	deletedAnswer, err := db.CreateAnswer(ctx, kept.ID, "user2", "Containers")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	if err := db.Delete(ctx, question.ID, "user1", false); err != nil {
+		t.Fatalf("failed to delete question: %v", err)
+	}
+
+	if err := db.DeleteAnswer(ctx, deletedAnswer.ID, "user2", false); err != nil {
+		t.Fatalf("failed to delete answer: %v", err)
+	}
+
+	// Still within the retention period
+	questions, answers, err := db.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	if err != nil || questions != 0 || answers != 0 {
+		t.Fatalf("expected nothing to be purged, got %d questions and %d answers, %v", questions, answers, err)
+	}
+
+	questions, answers, err = db.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to purge: %v", err)
+	}
+
+	if questions != 1 || answers != 1 {
+		t.Errorf("expected 1 question and 1 answer purged, got %d and %d", questions, answers)
+	}
+
+	if err := db.Restore(ctx, question.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected purged question to be gone, got %v", err)
+	}
+
+	if err := db.RestoreAnswer(ctx, deletedAnswer.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected purged answer to be gone, got %v", err)
+	}
+
+	// The answers of a purged question go with it
+	if err := db.DeleteAnswer(ctx, answer.ID, "user2", false); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected answer of the purged question to be gone, got %v", err)
+	}
+
+	if _, err := db.GetByID(ctx, kept.ID); err != nil {
+		t.Errorf("expected the other question to be kept, got %v", err)
+	}
+}
+
+func TestDeleteMissingReturnsNotFound(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if err := db.Delete(ctx, 999, "user1", false); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected repository.ErrNotFound for question, got %v", err)
+	}
+
+	if err := db.DeleteAnswer(ctx, 999, "user1", false); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected repository.ErrNotFound for answer, got %v", err)
+	}
+
+	if _, err := db.GetByID(ctx, 999); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected repository.ErrNotFound from GetByID, got %v", err)
+	}
+
+	_, err := db.CreateAnswer(ctx, 999, "user1", "Answer to nothing")
+	if !errors.Is(err, repository.ErrForeignKeyViolation) {
+		t.Errorf("expected repository.ErrForeignKeyViolation, got %v", err)
+	}
+
+	if constraint := repository.ViolatedConstraint(err); constraint != repository.ConstraintAnswerQuestion {
+		t.Errorf("expected the question foreign key, got %q", constraint)
+	}
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	_, err = db.CreateAnswer(ctx, question.ID, "stranger", "Answer from nobody")
+	if constraint := repository.ViolatedConstraint(err); constraint != repository.ConstraintAnswerUser {
+		t.Errorf("expected the user foreign key, got %q, %v", constraint, err)
+	}
+}
+
+func TestCreateAnswerBlocksQuestionDelete(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	deleted := make(chan error, 1)
+
+	err = db.WithTx(ctx, func(tx repository.Repositories) error {
+		exists, err := tx.Questions.Exists(ctx, question.ID)
+		if err != nil || !exists {
+			t.Fatalf("expected question to exist, got %v, %v", exists, err)
+		}
+
+		go func() {
+			deleted <- db.Delete(ctx, question.ID, "user1", false)
+		}()
+
+		select {
+		case err := <-deleted:
+			t.Fatalf("expected delete to wait for the transaction, it returned %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		_, err = tx.Answers.CreateAnswer(ctx, question.ID, "user2", "Go is a programming language")
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	if err := <-deleted; err != nil {
 		t.Fatalf("failed to delete question: %v", err)
 	}
 
-	_, err = db.GetAnswerByID(answer1.ID)
-	if err == nil {
-		t.Error("expected answer1 to be deleted, but it still exists")
+	if _, err := db.GetByID(ctx, question.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected question to be deleted after the answer was created, got %v", err)
+	}
+}
+
+// racingTransactor purges the question right after the existence check,
+// like a concurrent purge would if the check didn't lock the question.
+type racingTransactor struct {
+	db *postgres.DB
+}
+
+func (r racingTransactor) WithTx(ctx context.Context, fn func(tx repository.Repositories) error) error {
+	return r.db.WithTx(ctx, func(tx repository.Repositories) error {
+		tx.Questions = deletedAfterCheck{QuestionRepository: tx.Questions, db: r.db}
+		return fn(tx)
+	})
+}
+
+type deletedAfterCheck struct {
+	repository.QuestionRepository
+	db *postgres.DB
+}
+
+func (q deletedAfterCheck) Exists(ctx context.Context, id int) (bool, error) {
+	if err := q.db.Delete(ctx, id, "user1", false); err != nil {
+		return false, err
+	}
+
+	if _, _, err := q.db.PurgeDeleted(ctx, time.Now().Add(time.Hour)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func TestCreateAnswerOnDeletedQuestionIsNotFound(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answers := service.NewAnswerService(db, racingTransactor{db: db}, metrics.New())
+
+	_, err = answers.Create(ctx, question.ID, "user2", "Go is a programming language")
+	if !errors.Is(err, service.ErrQuestionNotFound) {
+		t.Fatalf("expected service.ErrQuestionNotFound, got %v", err)
+	}
+
+	if !errors.Is(err, repository.ErrForeignKeyViolation) {
+		t.Errorf("expected the foreign key violation as the cause, got %v", err)
+	}
+}
+
+func TestListQuestionsKeysetPagination(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, text := range []string{"First?", "Second?", "Third?"} {
+		if _, err := db.Create(ctx, "user1", text, "Details", nil); err != nil {
+			t.Fatalf("failed to create question: %v", err)
+		}
+	}
+
+	opts := repository.ListQuestionsOptions{Limit: 2, Sort: repository.SortCreatedAsc}
+
+	firstPage, next, err := db.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("failed to list first page: %v", err)
+	}
+
+	if len(firstPage) != 2 || next == nil {
+		t.Fatalf("expected 2 questions and a cursor, got %d questions and cursor %v", len(firstPage), next)
+	}
+
+	opts.Cursor = next
+
+	secondPage, next, err := db.List(ctx, opts)
+	if err != nil {
+		t.Fatalf("failed to list second page: %v", err)
+	}
+
+	if len(secondPage) != 1 || next != nil {
+		t.Fatalf("expected 1 question and no cursor, got %d questions and cursor %v", len(secondPage), next)
+	}
+
+	if secondPage[0].Title != "Third?" {
+		t.Errorf("expected last question to be %q, got %q", "Third?", secondPage[0].Title)
+	}
+}
+
+func TestListAnswersKeysetPagination(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
 	}
 
-	_, err = db.GetAnswerByID(answer2.ID)
-	if err == nil {
-		t.Error("expected answer2 to be deleted, but it still exists")
+	ids := make([]int, 4)
+	for i := range ids {
+		answer, err := db.CreateAnswer(ctx, question.ID, "user2", "Answer")
+		if err != nil {
+			t.Fatalf("failed to create answer: %v", err)
+		}
+		ids[i] = answer.ID
+	}
+
+	// Scores 0, 2, 1, 1, and the answer with the lowest score is accepted
+	votes := []struct {
+		answer int
+		userID string
+	}{{1, "user1"}, {1, "user2"}, {2, "user1"}, {3, "user2"}}
+	for _, vote := range votes {
+		if _, err := db.VoteAnswer(ctx, ids[vote.answer], vote.userID, 1); err != nil {
+			t.Fatalf("failed to vote: %v", err)
+		}
+	}
+	if err := db.AcceptAnswer(ctx, question.ID, ids[0]); err != nil {
+		t.Fatalf("failed to accept answer: %v", err)
+	}
+
+	listAll := func(sort repository.AnswerSort, limit int) []int {
+		opts := repository.ListAnswersOptions{Limit: limit, Sort: sort}
+
+		var listed []int
+		for range len(ids) + 1 {
+			page, next, err := db.ListAnswers(ctx, question.ID, opts)
+			if err != nil {
+				t.Fatalf("failed to list answers: %v", err)
+			}
+			for _, answer := range page {
+				listed = append(listed, answer.ID)
+			}
+			if next == nil {
+				return listed
+			}
+			opts.Cursor = next
+		}
+
+		t.Fatalf("expected the last page within %d pages", len(ids)+1)
+		return nil
+	}
+
+	tests := []struct {
+		sort     repository.AnswerSort
+		limit    int
+		expected []int
+	}{
+		// Ties on score are in creation order
+		{sort: repository.AnswerSortScore, limit: 1, expected: []int{ids[0], ids[1], ids[2], ids[3]}},
+		{sort: repository.AnswerSortScore, limit: 3, expected: []int{ids[0], ids[1], ids[2], ids[3]}},
+		{sort: repository.AnswerSortNewest, limit: 2, expected: []int{ids[0], ids[3], ids[2], ids[1]}},
+		{sort: repository.AnswerSortOldest, limit: 1, expected: []int{ids[0], ids[1], ids[2], ids[3]}},
+	}
+
+	for _, tt := range tests {
+		if listed := listAll(tt.sort, tt.limit); !slices.Equal(listed, tt.expected) {
+			t.Errorf("sort %s by %d: expected %v, got %v", tt.sort, tt.limit, tt.expected, listed)
+		}
+	}
+
+	answers, _, err := db.ListAnswers(ctx, question.ID, repository.ListAnswersOptions{Limit: 1, Sort: repository.AnswerSortScore})
+	if err != nil {
+		t.Fatalf("failed to list answers: %v", err)
+	}
+	if len(answers) != 1 || !answers[0].IsAccepted {
+		t.Errorf("expected the accepted answer first, got %+v", answers)
+	}
+
+	if _, _, err := db.ListAnswers(ctx, 999, repository.ListAnswersOptions{Limit: 1}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected repository.ErrNotFound for a missing question, got %v", err)
+	}
+}
+
+func TestListQuestionsSortedByAnswersCount(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	quiet, err := db.Create(ctx, "user1", "Quiet question", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	popular, err := db.Create(ctx, "user1", "Popular question", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	for _, user := range []string{"user1", "user2"} {
+		if _, err := db.CreateAnswer(ctx, popular.ID, user, "An answer"); err != nil {
+			t.Fatalf("failed to create answer: %v", err)
+		}
+	}
+
+	questions, _, err := db.List(ctx, repository.ListQuestionsOptions{Limit: 10, Sort: repository.SortAnswersCount})
+	if err != nil {
+		t.Fatalf("failed to list questions: %v", err)
+	}
+
+	if len(questions) != 2 || questions[0].ID != popular.ID || questions[1].ID != quiet.ID {
+		t.Fatalf("expected popular question first, got %+v", questions)
+	}
+
+	if questions[0].AnswersCount != 2 {
+		t.Errorf("expected answers_count 2, got %d", questions[0].AnswersCount)
+	}
+}
+
+func TestSearchMatchesQuestionsAndAnswers(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "How do goroutines communicate?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answer, err := db.CreateAnswer(ctx, question.ID, "user1", "Goroutines communicate through channels")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	if _, err := db.Create(ctx, "user1", "What is Docker?", "Details", nil); err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	hits, err := db.Search(ctx, "channels", 10)
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+
+	if len(hits) != 1 {
+		t.Fatalf("expected 1 hit, got %d", len(hits))
+	}
+
+	if hits[0].QuestionID != question.ID || hits[0].AnswerID == nil || *hits[0].AnswerID != answer.ID {
+		t.Errorf("expected hit on answer %d, got %+v", answer.ID, hits[0])
+	}
+
+	if !strings.Contains(hits[0].Snippet, "<mark>channels</mark>") {
+		t.Errorf("expected highlighted snippet, got %q", hits[0].Snippet)
+	}
+
+	hits, err = db.Search(ctx, "goroutines", 10)
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+
+	if len(hits) != 2 {
+		t.Fatalf("expected question and answer to match, got %d hits", len(hits))
+	}
+}
+
+func TestUpdateQuestionRecordsRevisions(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	for _, text := range []string{"What is Go?", "What is Go used for?"} {
+		if _, err := db.Update(ctx, question.ID, "user1", repository.QuestionChanges{Title: &text}); err != nil {
+			t.Fatalf("failed to update question: %v", err)
+		}
+	}
+
+	title := "What is Rust?"
+	if _, err := db.Update(ctx, question.ID, "user2", repository.QuestionChanges{Title: &title}); !errors.Is(err, repository.ErrNotOwner) {
+		t.Fatalf("expected repository.ErrNotOwner for another user, got %v", err)
+	}
+
+	updated, err := db.GetByID(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to get question: %v", err)
+	}
+
+	if updated.Title != "What is Go used for?" || updated.Body != "Details" {
+		t.Errorf("expected updated title and unchanged body, got %q, %q", updated.Title, updated.Body)
+	}
+
+	revisions, err := db.ListRevisions(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+
+	if revisions[0].Title != "What is Go?" || revisions[1].Title != "What is Go" {
+		t.Errorf("expected newest revision first, got %q then %q", revisions[0].Title, revisions[1].Title)
+	}
+}
+
+func TestUpdateAnswerRecordsRevisions(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answer, err := db.CreateAnswer(ctx, question.ID, "user2", "A language")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	for _, body := range []string{"A programming language", "A programming language by Google"} {
+		if _, err := db.UpdateAnswer(ctx, answer.ID, "user2", body); err != nil {
+			t.Fatalf("failed to update answer: %v", err)
+		}
+	}
+
+	if _, err := db.UpdateAnswer(ctx, answer.ID, "user1", "Not an answer"); !errors.Is(err, repository.ErrNotOwner) {
+		t.Fatalf("expected repository.ErrNotOwner for another user, got %v", err)
+	}
+
+	if err := db.DeleteAnswer(ctx, answer.ID, "user1", false); !errors.Is(err, repository.ErrNotOwner) {
+		t.Fatalf("expected repository.ErrNotOwner for another user, got %v", err)
+	}
+
+	revisions, err := db.ListAnswerRevisions(ctx, answer.ID)
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+
+	if len(revisions) != 2 || revisions[0].Body != "A programming language" || revisions[1].Body != "A language" {
+		t.Errorf("expected the replaced bodies, newest first, got %+v", revisions)
+	}
+}
+
+func TestCreateUserTwiceConflicts(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	_, err := db.CreateUser(ctx, "user1", "Another name", "")
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("expected repository.ErrConflict, got %v", err)
+	}
+}
+
+func TestListAnswersByUser(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	for _, text := range []string{"First", "Second", "Third"} {
+		if _, err := db.CreateAnswer(ctx, question.ID, "user2", text); err != nil {
+			t.Fatalf("failed to create answer: %v", err)
+		}
+	}
+
+	answers, next, err := db.ListAnswersByUser(ctx, "user2", 2, nil)
+	if err != nil {
+		t.Fatalf("failed to list answers: %v", err)
+	}
+
+	if len(answers) != 2 || next == nil || answers[0].Body != "Third" {
+		t.Fatalf("expected 2 newest answers and a cursor, got %+v and %v", answers, next)
+	}
+
+	answers, next, err = db.ListAnswersByUser(ctx, "user2", 2, next)
+	if err != nil {
+		t.Fatalf("failed to list answers: %v", err)
+	}
+
+	if len(answers) != 1 || next != nil || answers[0].Body != "First" {
+		t.Fatalf("expected the oldest answer on the last page, got %+v and %v", answers, next)
+	}
+}
+
+func TestAnswerRequiresExistingUser(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	if _, err := db.CreateAnswer(ctx, question.ID, "ghost", "Boo"); err == nil {
+		t.Error("expected answer from unknown user to be rejected")
+	}
+}
+
+func TestVotesMaintainScore(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answer, err := db.CreateAnswer(ctx, question.ID, "user1", "A language")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	steps := []struct {
+		userID        string
+		value         int
+		expectedScore int
+	}{
+		{userID: "user1", value: 1, expectedScore: 1},
+		{userID: "user1", value: 1, expectedScore: 1}, // Same vote twice counts once
+		{userID: "user2", value: 1, expectedScore: 2},
+		{userID: "user1", value: -1, expectedScore: 0}, // Changed mind
+	}
+
+	for i, step := range steps {
+		score, err := db.VoteAnswer(ctx, answer.ID, step.userID, step.value)
+		if err != nil {
+			t.Fatalf("step %d: failed to vote: %v", i, err)
+		}
+
+		if score != step.expectedScore {
+			t.Errorf("step %d: expected score %d, got %d", i, step.expectedScore, score)
+		}
+	}
+
+	stored, err := db.GetAnswerByID(ctx, answer.ID)
+	if err != nil {
+		t.Fatalf("failed to get answer: %v", err)
+	}
+
+	if stored.Score != 0 {
+		t.Errorf("expected stored score 0, got %d", stored.Score)
+	}
+
+	if _, err := db.Vote(ctx, question.ID, "user2", 1); err != nil {
+		t.Fatalf("failed to vote for question: %v", err)
+	}
+
+	questions, _, err := db.List(ctx, repository.ListQuestionsOptions{Limit: 10, Sort: repository.SortScore})
+	if err != nil {
+		t.Fatalf("failed to list questions: %v", err)
+	}
+
+	if len(questions) != 1 || questions[0].Score != 1 {
+		t.Errorf("expected question with score 1, got %+v", questions)
+	}
+}
+
+func TestAcceptAnswer(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	other, err := db.Create(ctx, "user1", "What is Docker?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answer, err := db.CreateAnswer(ctx, question.ID, "user2", "A language")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	if err := db.AcceptAnswer(ctx, other.ID, answer.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected answer of another question to be rejected, got %v", err)
+	}
+
+	if err := db.AcceptAnswer(ctx, question.ID, answer.ID); err != nil {
+		t.Fatalf("failed to accept answer: %v", err)
+	}
+
+	fetched, err := db.GetAnswerByID(ctx, answer.ID)
+	if err != nil {
+		t.Fatalf("failed to get answer: %v", err)
+	}
+
+	if !fetched.IsAccepted {
+		t.Error("expected answer to be accepted")
+	}
+
+	if err := db.DeleteAnswer(ctx, answer.ID, "user2", false); err != nil {
+		t.Fatalf("failed to delete answer: %v", err)
+	}
+
+	fetchedQuestion, err := db.GetByID(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to get question: %v", err)
+	}
+
+	if fetchedQuestion.AcceptedAnswerID != nil {
+		t.Errorf("expected accepted answer to be cleared on delete, got %d", *fetchedQuestion.AcceptedAnswerID)
+	}
+}
+
+func TestListQuestionsByTags(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	both, err := db.Create(ctx, "user1", "How to use pgx from Go?", "Details", []string{"go", "postgres"})
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	goOnly, err := db.Create(ctx, "user1", "What is a goroutine?", "Details", []string{"go"})
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	if _, err := db.Create(ctx, "user1", "What is Docker?", "Details", nil); err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	all, _, err := db.List(ctx, repository.ListQuestionsOptions{Limit: 10, Tags: []string{"go", "postgres"}})
+	if err != nil {
+		t.Fatalf("failed to list questions: %v", err)
+	}
+
+	if len(all) != 1 || all[0].ID != both.ID {
+		t.Fatalf("expected only question with both tags, got %+v", all)
+	}
+
+	if len(all[0].Tags) != 2 {
+		t.Errorf("expected tags to be preloaded, got %+v", all[0].Tags)
+	}
+
+	matchAny, _, err := db.List(ctx, repository.ListQuestionsOptions{Limit: 10, Tags: []string{"go", "postgres"}, MatchAnyTag: true})
+	if err != nil {
+		t.Fatalf("failed to list questions: %v", err)
+	}
+
+	if len(matchAny) != 2 {
+		t.Fatalf("expected 2 questions with any tag, got %d", len(matchAny))
+	}
+
+	tags, err := db.ListTags(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to list tags: %v", err)
+	}
+
+	if len(tags) != 2 || tags[0].Name != "go" || tags[0].QuestionsCount != 2 {
+		t.Errorf("expected go to be the most used tag, got %+v", tags)
+	}
+
+	if _, err := db.Update(ctx, goOnly.ID, "user1", repository.QuestionChanges{Tags: &[]string{"concurrency"}}); err != nil {
+		t.Fatalf("failed to update tags: %v", err)
+	}
+
+	updated, err := db.GetByID(ctx, goOnly.ID)
+	if err != nil {
+		t.Fatalf("failed to get question: %v", err)
+	}
+
+	if len(updated.Tags) != 1 || updated.Tags[0].Name != "concurrency" {
+		t.Errorf("expected tags to be replaced, got %+v", updated.Tags)
+	}
+}
+
+func TestComments(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	question, err := db.Create(ctx, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answer, err := db.CreateAnswer(ctx, question.ID, "user2", "A language")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	parent, err := db.CreateComment(ctx, repository.CommentTarget{QuestionID: question.ID}, nil, "user2", "Which version?")
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+
+	if _, err := db.CreateComment(ctx, repository.CommentTarget{QuestionID: question.ID}, &parent.ID, "user1", "1.25"); err != nil {
+		t.Fatalf("failed to create reply: %v", err)
+	}
+
+	if _, err := db.CreateComment(ctx, repository.CommentTarget{AnswerID: answer.ID}, nil, "user1", "Thanks"); err != nil {
+		t.Fatalf("failed to create answer comment: %v", err)
+	}
+
+	onQuestion, err := db.ListComments(ctx, repository.CommentTarget{QuestionID: question.ID})
+	if err != nil {
+		t.Fatalf("failed to list comments: %v", err)
+	}
+
+	if len(onQuestion) != 2 || onQuestion[1].ParentID == nil || *onQuestion[1].ParentID != parent.ID {
+		t.Fatalf("expected comment and its reply, got %+v", onQuestion)
+	}
+
+	all, err := db.ListQuestionComments(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to list question comments: %v", err)
+	}
+
+	if len(all) != 3 {
+		t.Fatalf("expected comments on the question and the answer, got %d", len(all))
+	}
+
+	if err := db.DeleteComment(ctx, parent.ID); err != nil {
+		t.Fatalf("failed to delete comment: %v", err)
+	}
+
+	all, err = db.ListQuestionComments(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("failed to list question comments: %v", err)
+	}
+
+	if len(all) != 1 || all[0].AnswerID == nil {
+		t.Errorf("expected replies to be deleted with their parent, got %+v", all)
+	}
+}
+
+func TestQueriesAreTraced(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	db, cleanup := setupTracedTestDB(t, tp)
+	defer cleanup()
+
+	question, err := db.Create(context.Background(), "user1", "Traced question", "Body", []string{"go"})
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	exporter.Reset()
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "GET /questions/{id}")
+	if _, err := db.GetByID(ctx, question.ID); err != nil {
+		t.Fatalf("failed to get question: %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	query, ok := byName["SELECT questions"]
+	if !ok {
+		t.Fatalf("expected a span for the question query, got %d spans", len(spans))
+	}
+	if query.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected the question query to be a child of the request span")
+	}
+
+	// Preloads run inside the question query
+	tags, ok := byName["SELECT tags"]
+	if !ok {
+		t.Fatal("expected a span for the tags preload")
+	}
+	if tags.Parent.SpanID() != query.SpanContext.SpanID() {
+		t.Error("expected the tags preload to be a child of the question query")
+	}
+}
+
+func TestRateLimitStore(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	store := postgres.NewRateLimitStore(db, slog.New(slog.DiscardHandler))
+	// Slow enough for nothing to refill during the test
+	limit := ratelimit.Limit{Rate: 1.0 / 3600, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "write:user:user1", limit)
+		if err != nil {
+			t.Fatalf("failed to take a token: %v", err)
+		}
+		if !result.Allowed || result.Remaining != i {
+			t.Fatalf("expected request to be allowed with %d remaining, got %+v", i, result)
+		}
+	}
+
+	result, err := store.Take(ctx, "write:user:user1", limit)
+	if err != nil {
+		t.Fatalf("failed to take a token: %v", err)
+	}
+	if result.Allowed || result.RetryAfter <= 0 {
+		t.Errorf("expected the burst to be used up, got %+v", result)
+	}
+
+	result, err = store.Take(ctx, "write:user:user2", limit)
+	if err != nil {
+		t.Fatalf("failed to take a token: %v", err)
+	}
+	if !result.Allowed || result.Remaining != 2 {
+		t.Errorf("expected other keys to have their own bucket, got %+v", result)
+	}
+}
+
+func TestAuditLogRecordsChanges(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := audit.WithActor(context.Background(), audit.Actor{UserID: "user1", RequestID: "req-1", ClientIP: "203.0.113.7"})
+
+	question, err := db.Create(ctx, "user1", "What is Go", "Details", []string{"go"})
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	title := "What is Go?"
+	if _, err := db.Update(ctx, question.ID, "user1", repository.QuestionChanges{Title: &title}); err != nil {
+		t.Fatalf("failed to update question: %v", err)
+	}
+	// Saving the question as it is isn't recorded
+	if _, err := db.Update(ctx, question.ID, "user1", repository.QuestionChanges{Title: &title}); err != nil {
+		t.Fatalf("failed to update question: %v", err)
+	}
+
+	// A failed change leaves no event behind
+	if err := db.AcceptAnswer(ctx, question.ID, 12345); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected repository.ErrNotFound, got %v", err)
+	}
+
+	if err := db.Delete(ctx, question.ID, "user1", false); err != nil {
+		t.Fatalf("failed to delete question: %v", err)
+	}
+
+	events, _, err := db.ListAuditEvents(context.Background(), repository.AuditFilter{EntityType: audit.EntityQuestion, EntityID: strconv.Itoa(question.ID)}, 10, nil)
+	if err != nil {
+		t.Fatalf("failed to list audit events: %v", err)
+	}
+
+	var actions []string
+	for _, event := range events {
+		actions = append(actions, event.Action)
+	}
+	expected := []string{audit.QuestionDeleted, audit.QuestionUpdated, audit.QuestionCreated}
+	if !slices.Equal(actions, expected) {
+		t.Fatalf("expected events %v, newest first, got %v", expected, actions)
+	}
+
+	updated := events[1]
+	if updated.Actor == nil || *updated.Actor != "user1" || updated.RequestID == nil || *updated.RequestID != "req-1" ||
+		updated.ClientIP == nil || *updated.ClientIP != "203.0.113.7" {
+		t.Errorf("expected the actor of the request, got %v, %v, %v", updated.Actor, updated.RequestID, updated.ClientIP)
+	}
+
+	var before, after struct {
+		Title     string     `json:"title"`
+		DeletedAt *time.Time `json:"deleted_at"`
+	}
+	if err := json.Unmarshal(updated.Before, &before); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	if err := json.Unmarshal(updated.After, &after); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	if before.Title != "What is Go" || after.Title != "What is Go?" {
+		t.Errorf("expected the title before and after the update, got %q and %q", before.Title, after.Title)
+	}
+
+	if err := json.Unmarshal(events[0].After, &after); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	if after.DeletedAt == nil {
+		t.Error("expected the deleted question to be in the trash after the delete")
+	}
+
+	if events[2].Before != nil {
+		t.Errorf("expected no snapshot before the question was created, got %s", events[2].Before)
+	}
+}
+
+func TestAuditLogFilters(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	start := time.Now()
+	asUser1 := audit.WithActor(context.Background(), audit.Actor{UserID: "user1"})
+	asUser2 := audit.WithActor(context.Background(), audit.Actor{UserID: "user2"})
+
+	question, err := db.Create(asUser1, "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	answer, err := db.CreateAnswer(asUser2, question.ID, "user2", "A language")
+	if err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	if _, err := db.VoteAnswer(asUser1, answer.ID, "user1", 1); err != nil {
+		t.Fatalf("failed to vote: %v", err)
+	}
+
+	events, _, err := db.ListAuditEvents(context.Background(), repository.AuditFilter{Actor: "user2"}, 10, nil)
+	if err != nil {
+		t.Fatalf("failed to list audit events: %v", err)
+	}
+	if len(events) != 1 || events[0].Action != audit.AnswerCreated {
+		t.Errorf("expected the answer of user2, got %+v", events)
+	}
+
+	// Users registered by setupTestDB are before start
+	events, next, err := db.ListAuditEvents(context.Background(), repository.AuditFilter{From: &start}, 2, nil)
+	if err != nil {
+		t.Fatalf("failed to list audit events: %v", err)
+	}
+	if len(events) != 2 || events[0].Action != audit.AnswerVoted || next == nil {
+		t.Fatalf("expected the vote first and a next page, got %+v, %+v", events, next)
+	}
+
+	events, next, err = db.ListAuditEvents(context.Background(), repository.AuditFilter{From: &start}, 2, next)
+	if err != nil {
+		t.Fatalf("failed to list audit events: %v", err)
+	}
+	if len(events) != 1 || events[0].Action != audit.QuestionCreated || next != nil {
+		t.Errorf("expected only the question on the last page, got %+v, %+v", events, next)
+	}
+
+	// The log is append-only
+	sqlDB, err := db.GetDB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	if _, err := sqlDB.Exec("UPDATE audit_events SET actor = 'someone-else'"); err == nil {
+		t.Error("expected audit events not to be updatable")
+	}
+	if _, err := sqlDB.Exec("DELETE FROM audit_events"); err == nil {
+		t.Error("expected audit events not to be deletable")
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	deliveries := make(chan received, 10)
+	var fail atomic.Bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- received{header: r.Header, body: body}
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+
+	ctx := context.Background()
+	subscription, err := db.CreateWebhook(ctx, receiver.URL, []string{audit.QuestionCreated}, "secret", "user1")
+	if err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+
+	question, err := db.Create(audit.WithActor(ctx, audit.Actor{UserID: "user1"}), "user1", "What is Go?", "Details", nil)
+	if err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+
+	// Not subscribed to
+	if _, err := db.CreateAnswer(ctx, question.ID, "user2", "A language"); err != nil {
+		t.Fatalf("failed to create answer: %v", err)
+	}
+
+	dispatcher := webhook.NewDispatcher(db, time.Minute, 5*time.Second, webhook.Backoff{Min: time.Minute, Max: time.Hour, MaxAttempts: 3}, slog.New(slog.DiscardHandler))
+	if n := dispatcher.Dispatch(ctx); n != 1 {
+		t.Fatalf("expected 1 delivery, got %d", n)
+	}
+
+	got := <-deliveries
+	timestamp, err := strconv.ParseInt(got.header.Get(webhook.HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+	if got.header.Get(webhook.HeaderSignature) != webhook.Sign("secret", timestamp, got.body) {
+		t.Error("expected the payload to be signed with the secret of the subscription")
+	}
+
+	var payload models.WebhookPayload
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.Event != audit.QuestionCreated || payload.EntityID != strconv.Itoa(question.ID) || payload.Actor == nil || *payload.Actor != "user1" {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+
+	// Delivered, nothing left to send
+	if n := dispatcher.Dispatch(ctx); n != 0 {
+		t.Errorf("expected no delivery left, got %d", n)
+	}
+
+	fail.Store(true)
+	if _, err := db.Create(ctx, "user2", "What is Rust?", "Details", nil); err != nil {
+		t.Fatalf("failed to create question: %v", err)
+	}
+	if n := dispatcher.Dispatch(ctx); n != 1 {
+		t.Fatalf("expected 1 delivery, got %d", n)
+	}
+	<-deliveries
+
+	// Retried after the backoff, not right away
+	if n := dispatcher.Dispatch(ctx); n != 0 {
+		t.Errorf("expected the failed delivery to wait for its retry, got %d", n)
+	}
+
+	history, next, err := db.ListWebhookDeliveries(ctx, subscription.ID, 10, nil)
+	if err != nil {
+		t.Fatalf("failed to list deliveries: %v", err)
+	}
+	if len(history) != 2 || next != nil {
+		t.Fatalf("expected 2 deliveries, got %+v", history)
+	}
+
+	failed, delivered := history[0], history[1]
+	if failed.Status != models.DeliveryPending || failed.Attempts != 1 || failed.LastStatusCode == nil || *failed.LastStatusCode != http.StatusInternalServerError {
+		t.Errorf("expected the failed delivery to be pending a retry, got %+v", failed)
+	}
+	if delivered.Status != models.DeliveryDelivered || delivered.Attempts != 1 || delivered.DeliveredAt == nil {
+		t.Errorf("expected the first delivery to be delivered, got %+v", delivered)
+	}
+
+	if err := db.DeleteWebhook(ctx, subscription.ID); err != nil {
+		t.Fatalf("failed to delete webhook: %v", err)
+	}
+	if _, _, err := db.ListWebhookDeliveries(ctx, subscription.ID, 10, nil); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
 	}
 }